@@ -22,7 +22,7 @@ for i in $#
 do
     if [[ "$i" == "1" && "${!i}" == "ok" ]]
     then
-        go build -o gdr main.go sources.go graph.go data.go text.go
+        go build -o gdr ./cmd/gdr
         if [ $? == 0 ]
         then
             #mv gdr ~/bin/gdr
@@ -30,7 +30,10 @@ do
         else
             echo "build error!"
         fi
+    elif [[ "$i" == "1" && "${!i}" == "test" ]]
+    then
+        go test -race ./...
     else
-        go run main.go sources.go graph.go data.go text.go
+        go run ./cmd/gdr
     fi
 done
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/DKazakov/gdr-go/logging"
+	"github.com/DKazakov/gdr-go/market"
+	"github.com/DKazakov/gdr-go/provider"
+	"net"
+	"net/http"
+	"time"
+)
+
+type ApiLabels struct {
+	Price        string `json:"price"`
+	ScaledVolume string `json:"scaled_volume,omitempty"`
+	ScaledGdr    string `json:"scaled_gdr,omitempty"`
+	Waterline    string `json:"waterline"`
+}
+type ApiExtremum struct {
+	Price  float64 `json:"price"`
+	Volume float64 `json:"volume"`
+	Gdr    float64 `json:"gdr"`
+	Chart  float64 `json:"chart"`
+}
+type ApiSeries struct {
+	Name         string      `json:"name"`
+	Time         []int64     `json:"time"`
+	Price        []float64   `json:"price"`
+	Volume       []float64   `json:"volume,omitempty"`
+	ScaledVolume []float64   `json:"scaled_volume,omitempty"`
+	Gdr          []float64   `json:"gdr,omitempty"`
+	ScaledGdr    []float64   `json:"scaled_gdr,omitempty"`
+	Waterline    float64     `json:"waterline"`
+	Labels       ApiLabels   `json:"labels"`
+	Maximum      ApiExtremum `json:"maximum"`
+	Minimum      ApiExtremum `json:"minimum"`
+}
+type ApiSource struct {
+	Url     string     `json:"url"`
+	Status  string     `json:"status"`
+	Error   string     `json:"error,omitempty"`
+	Checked *time.Time `json:"checked,omitempty"`
+	Updated *time.Time `json:"updated,omitempty"`
+}
+type ApiData struct {
+	LastPrice   float64              `json:"last_price"`
+	LastClose   float64              `json:"last_close"`
+	Gdr         float64              `json:"gdr"`
+	GdrForecast float64              `json:"gdr_forecast"`
+	Dollar      float64              `json:"dollar"`
+	LastUpdate  time.Time            `json:"last_update"`
+	Series      []ApiSeries          `json:"series"`
+	Sources     map[string]ApiSource `json:"sources"`
+}
+
+func (self *ApiData) Init(data *market.Data, sources map[string]*provider.Source) *ApiData {
+	self.LastPrice = data.LastPrice
+	self.LastClose = data.LastClose
+	self.Gdr = data.Gdr
+	self.GdrForecast = data.GdrForecast
+	self.Dollar = data.Dollar
+	self.LastUpdate = data.LastUpdate
+
+	for _, item := range data.Graph {
+		self.Series = append(self.Series, apiSeries(item))
+	}
+
+	self.Sources = map[string]ApiSource{}
+	for name, item := range sources {
+		self.Sources[name] = apiSource(item)
+	}
+
+	return self
+}
+
+func apiSeries(item market.GraphData) (series ApiSeries) {
+	series.Name = item.Name
+	for _, e := range item.Y {
+		series.Time = append(series.Time, e.UnixNano()/int64(time.Millisecond))
+	}
+	series.Price = item.X
+	series.Volume = item.Volume
+	series.ScaledVolume = item.XV
+	series.Gdr = item.Gdr
+	series.ScaledGdr = item.XGdr
+	series.Waterline = item.Waterline
+	if item.Labels != nil {
+		series.Labels = ApiLabels{item.Labels.X, item.Labels.XV, item.Labels.XGdr, item.Labels.Waterline}
+	}
+	if item.Maximum != nil && item.Minimum != nil {
+		series.Maximum = ApiExtremum{item.Maximum.X, item.Maximum.XV, item.Maximum.XGdr, item.Maximum.Chart}
+		series.Minimum = ApiExtremum{item.Minimum.X, item.Minimum.XV, item.Minimum.XGdr, item.Minimum.Chart}
+	}
+
+	return series
+}
+func apiSource(item *provider.Source) (source ApiSource) {
+	stats := item.Snapshot()
+	source.Url = item.Url
+	source.Error = stats.LastError
+	source.Status = stats.Status()
+	if !stats.Checked.IsZero() {
+		checked := stats.Checked
+		source.Checked = &checked
+	}
+	if !stats.Updated.IsZero() {
+		updated := stats.Updated
+		source.Updated = &updated
+	}
+
+	return source
+}
+
+// serve runs the http server until ctx is cancelled and returns once it has
+// shut down. Requests get ctx as a base, so event streams end on shutdown
+// instead of holding it.
+func serve(ctx context.Context, addr string, sources map[string]*provider.Source) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/data", func(w http.ResponseWriter, r *http.Request) {
+		data := current()
+		if data == nil {
+			http.Error(w, "data is not loaded yet", http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(new(ApiData).Init(data, sources)); err != nil {
+			logging.Error("api encoding error", "err", err)
+		}
+	})
+	dashboard(mux)
+	metrics(mux, sources)
+	exerciseApi(mux)
+
+	server := &http.Server{
+		Addr:        addr,
+		Handler:     mux,
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-ctx.Done()
+		timeout, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(timeout); err != nil {
+			logging.Warn("http api shutdown error", "err", err)
+		}
+	}()
+
+	logging.Info("http api listening", "addr", addr)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		logging.Error("http api error", "addr", addr, "err", err)
+	}
+	<-stopped
+
+	return
+}
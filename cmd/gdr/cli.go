@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/DKazakov/gdr-go/market"
+	"github.com/DKazakov/gdr-go/provider"
+	"github.com/DKazakov/gdr-go/valuation"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const ladderRows = 40
+
+type Snapshot struct {
+	fields []string
+	rows   [][]float64
+	single bool
+}
+
+var commands = map[string]func(*market.Data) *Snapshot{
+	"price":  priceSnapshot,
+	"value":  valueSnapshot,
+	"gdr":    gdrSnapshot,
+	"ladder": ladderSnapshot,
+}
+
+func command(ctx context.Context, args []string, sources map[string]*provider.Source) int {
+	switch args[0] {
+	case "export":
+		return exportCommand(ctx, args[1:], sources)
+	case "series":
+		return seriesCommand(ctx, args[1:], sources)
+	case "exercise":
+		return exerciseCommand(ctx, args[1:], sources)
+	}
+
+	snapshot, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q, use one of: price, value, gdr, ladder, export, series, exercise\n", args[0])
+		return 2
+	}
+
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	format := flags.String("format", "text", "output format: text, json or csv")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if !knownFormat(*format, "text", "json", "csv") {
+		return 2
+	}
+
+	data, ok := fetch(ctx, sources)
+	if !ok {
+		return 1
+	}
+	if err := snapshot(data).write(os.Stdout, *format); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+// knownFormat checks the format of a command before it fetches anything, so
+// a typo fails at once as a usage error.
+func knownFormat(format string, formats ...string) bool {
+	for _, item := range formats {
+		if item == format {
+			return true
+		}
+	}
+	fmt.Fprintf(os.Stderr, "unknown format %q, use one of: %s\n", format, strings.Join(formats, ", "))
+
+	return false
+}
+
+// fetch updates data for a command, which fails if any source failed rather
+// than print values computed without it.
+func fetch(ctx context.Context, sources map[string]*provider.Source) (*market.Data, bool) {
+	data, err := provider.Update(ctx, sources, provider.Get, publish)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "fetch error:", err)
+		return data, false
+	}
+
+	return data, true
+}
+
+func priceSnapshot(data *market.Data) *Snapshot {
+	return &Snapshot{
+		fields: []string{"last_price", "last_close", "change", "dollar"},
+		rows:   [][]float64{{data.LastPrice, data.LastClose, data.LastPrice - data.LastClose, data.Dollar}},
+		single: true,
+	}
+}
+func valueSnapshot(data *market.Data) *Snapshot {
+	dprice := data.Gdr * data.LastPrice
+
+	return &Snapshot{
+		fields: []string{"gdr", "value_usd", "value_rub", "dollar"},
+		rows:   [][]float64{{data.Gdr, dprice, dprice * data.Dollar, data.Dollar}},
+		single: true,
+	}
+}
+func gdrSnapshot(data *market.Data) *Snapshot {
+	return &Snapshot{
+		fields: []string{"gdr", "forecast", "forecast_rub"},
+		rows:   [][]float64{{data.Gdr, data.GdrForecast, data.GdrForecast * data.LastPrice * data.Dollar}},
+		single: true,
+	}
+}
+func ladderSnapshot(data *market.Data) *Snapshot {
+	self := &Snapshot{fields: []string{"price", "value_usd", "value_rub"}}
+
+	for _, row := range valuation.Ladder(data.LastPrice, data.Dollar, 0, ladderRows) {
+		self.rows = append(self.rows, []float64{row.Price, row.Value, row.Rvalue * 1000})
+	}
+
+	return self
+}
+
+func (self *Snapshot) write(out io.Writer, format string) (err error) {
+	switch format {
+	case "text":
+		for _, row := range self.rows {
+			line := []string{}
+			for i, value := range row {
+				if self.single {
+					fmt.Fprintf(out, "%s: %.2f\n", self.fields[i], value)
+				} else {
+					line = append(line, fmt.Sprintf("%.2f", value))
+				}
+			}
+			if !self.single {
+				fmt.Fprintln(out, strings.Join(line, "\t"))
+			}
+		}
+	case "csv":
+		writer := csv.NewWriter(out)
+		writer.Write(self.fields)
+		for _, row := range self.rows {
+			record := []string{}
+			for _, value := range row {
+				record = append(record, strconv.FormatFloat(value, 'f', -1, 64))
+			}
+			writer.Write(record)
+		}
+		writer.Flush()
+		err = writer.Error()
+	case "json":
+		objects := []map[string]float64{}
+		for _, row := range self.rows {
+			object := map[string]float64{}
+			for i, value := range row {
+				object[self.fields[i]] = value
+			}
+			objects = append(objects, object)
+		}
+
+		encoder := json.NewEncoder(out)
+		if self.single && len(objects) == 1 {
+			err = encoder.Encode(objects[0])
+		} else {
+			err = encoder.Encode(objects)
+		}
+	default:
+		err = fmt.Errorf("unknown format %q, use one of: text, json, csv", format)
+	}
+
+	return err
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/DKazakov/gdr-go/logging"
+	"github.com/DKazakov/gdr-go/market"
+	"github.com/DKazakov/gdr-go/provider"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+var (
+	stateMu sync.RWMutex
+	state   *market.Data
+)
+
+func publish(data *market.Data) {
+	stateMu.Lock()
+	state = data
+	stateMu.Unlock()
+	notify()
+
+	if *statePath != "" {
+		if err := writeState(*statePath, data); err != nil {
+			logging.Error("state writing error", "path", *statePath, "err", err)
+		}
+	}
+
+	return
+}
+func current() *market.Data {
+	stateMu.RLock()
+	defer stateMu.RUnlock()
+
+	return state
+}
+
+func writeState(path string, data *market.Data) error {
+	content := fmt.Sprintf(
+		"lastprice %.2f\nlastclose %.2f\ngdr %.2f\nforecast %.2f\ndollar %.4f\nlastupdate %s\nupdated %s\n",
+		data.LastPrice,
+		data.LastClose,
+		data.Gdr,
+		data.GdrForecast,
+		data.Dollar,
+		data.LastUpdate.Format(time.RFC3339),
+		time.Now().Format(time.RFC3339),
+	)
+
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, []byte(content), 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// shutdown returns a context cancelled by SIGINT, SIGTERM, SIGHUP or cancel.
+func shutdown() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+
+	go func() {
+		select {
+		case sig := <-signals:
+			logging.Info("stopping", "signal", sig)
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(signals)
+	}()
+
+	return ctx, cancel
+}
+
+func daemon(ctx context.Context, sources map[string]*provider.Source) {
+	data, _ := provider.Update(ctx, sources, provider.Get, publish)
+	logging.Info("daemon started", "price", data.LastPrice, "gdr", data.Gdr)
+
+	updateTicker := time.NewTicker(provider.UpdateTick)
+	defer updateTicker.Stop()
+
+	for {
+		select {
+		case <-updateTicker.C:
+			data, _ = provider.Update(ctx, sources, provider.Get, publish)
+			logging.Info("updated", "price", data.LastPrice, "gdr", data.Gdr)
+		case <-ctx.Done():
+			logging.Info("daemon stopped")
+			return
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/DKazakov/gdr-go/logging"
+	"github.com/DKazakov/gdr-go/provider"
+	"github.com/DKazakov/gdr-go/tui"
+	"github.com/DKazakov/gdr-go/valuation"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+func exerciseCommand(ctx context.Context, args []string, sources map[string]*provider.Source) int {
+	flags := flag.NewFlagSet("exercise", flag.ContinueOnError)
+	format := flags.String("format", "text", "output format: text or json")
+	price := flags.Float64("price", 0, "what-if share price, current price if not set")
+	dollar := flags.Float64("dollar", 0, "what-if exchange rate, current rate if not set")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if !knownFormat(*format, "text", "json") {
+		return 2
+	}
+
+	data, ok := fetch(ctx, sources)
+	if !ok {
+		return 1
+	}
+	exercises := []*valuation.Exercise{new(valuation.Exercise).Init(data.LastPrice, data.Dollar)}
+	if *price > 0 || *dollar > 0 {
+		if *price <= 0 {
+			*price = data.LastPrice
+		}
+		if *dollar <= 0 {
+			*dollar = data.Dollar
+		}
+		exercises = append(exercises, new(valuation.Exercise).Init(*price, *dollar))
+	}
+
+	switch *format {
+	case "json":
+		if err := json.NewEncoder(os.Stdout).Encode(exercises); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	case "text":
+		for _, exercise := range exercises {
+			for _, line := range tui.ExerciseLines(exercise) {
+				fmt.Println(line)
+			}
+		}
+	}
+
+	return 0
+}
+
+func exerciseApi(mux *http.ServeMux) {
+	mux.HandleFunc("/api/exercise", func(w http.ResponseWriter, r *http.Request) {
+		data := current()
+		if data == nil {
+			http.Error(w, "data is not loaded yet", http.StatusServiceUnavailable)
+			return
+		}
+
+		price, dollar := data.LastPrice, data.Dollar
+		if value, err := strconv.ParseFloat(r.URL.Query().Get("price"), 64); err == nil && value > 0 {
+			price = value
+		}
+		if value, err := strconv.ParseFloat(r.URL.Query().Get("dollar"), 64); err == nil && value > 0 {
+			dollar = value
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(new(valuation.Exercise).Init(price, dollar)); err != nil {
+			logging.Error("exercise encoding error", "err", err)
+		}
+	})
+
+	return
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/DKazakov/gdr-go/provider"
+	"github.com/DKazakov/gdr-go/render"
+	"github.com/wcharczuk/go-chart"
+	"os"
+	"strconv"
+)
+
+var exportOptions = exportFlags(flag.CommandLine, &render.ExportOptions{
+	Dir:    ".",
+	Format: "png",
+	Width:  1600,
+	Height: 800,
+	DPI:    chart.DefaultDPI,
+})
+
+// exportFlags binds the export flags to a copy of defaults, so the export
+// command takes the global flags as its defaults.
+func exportFlags(flags *flag.FlagSet, defaults *render.ExportOptions) *render.ExportOptions {
+	options := *defaults
+	flags.StringVar(&options.Dir, "export-dir", defaults.Dir, "directory to export charts to")
+	flags.StringVar(&options.Format, "export-format", defaults.Format, "chart export format: png or svg")
+	flags.IntVar(&options.Width, "export-width", defaults.Width, "exported chart width in pixels")
+	flags.IntVar(&options.Height, "export-height", defaults.Height, "exported chart height in pixels")
+	flags.Float64Var(&options.DPI, "export-dpi", defaults.DPI, "exported chart resolution")
+
+	return &options
+}
+
+func exportCommand(ctx context.Context, args []string, sources map[string]*provider.Source) int {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	options := exportFlags(flags, exportOptions)
+	page := flags.String("page", "all", "page to export: 1-5 or all")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if !knownFormat(options.Format, "png", "svg") {
+		return 2
+	}
+
+	data, ok := fetch(ctx, sources)
+	if !ok {
+		return 1
+	}
+	graph := new(render.Graph).Init(data)
+
+	var (
+		paths []string
+		err   error
+	)
+	if *page == "all" {
+		paths, err = graph.ExportAll(options)
+	} else {
+		number, _ := strconv.Atoi(*page)
+		if number < 1 || number > graph.PageCount() {
+			fmt.Fprintf(os.Stderr, "wrong page %q, use 1-%d or all\n", *page, graph.PageCount())
+			return 2
+		}
+		graph.SetPage(number - 1)
+
+		var path string
+		path, err = graph.Export(options)
+		paths = append(paths, path)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "export error:", err)
+		return 1
+	}
+
+	for _, path := range paths {
+		fmt.Println(path)
+	}
+
+	return 0
+}
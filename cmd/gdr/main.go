@@ -0,0 +1,107 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/DKazakov/gdr-go/locale"
+	"github.com/DKazakov/gdr-go/logging"
+	"github.com/DKazakov/gdr-go/market"
+	"github.com/DKazakov/gdr-go/provider"
+	"github.com/DKazakov/gdr-go/render"
+	"github.com/DKazakov/gdr-go/tui"
+	"github.com/DKazakov/gdr-go/valuation"
+	"os"
+	"time"
+)
+
+var (
+	daemonMode   = flag.Bool("daemon", false, "run without terminal, only fetch data and keep state")
+	statePath    = flag.String("state", "", "file to write current state to after every update")
+	httpAddr     = flag.String("http", "", "address to serve JSON API and web dashboard on, e.g. :8080")
+	seriesFormat = flag.String("series-format", "csv", "price series export format: csv or jsonl")
+	langOption   = flag.String("lang", "", "interface language: ru or en, taken from LC_ALL, LC_MESSAGES or LANG if not set")
+	themeOption  = flag.String("theme", "light", "colour theme: light, dark, high-contrast or deuteranopia")
+	zoneOption   = flag.String("zone", "exchange", "time zone for chart axes and times: exchange, local or utc")
+)
+
+const shutdownTimeout time.Duration = 5 * time.Second
+
+// packageFlags binds flags to the settings of the library packages.
+func packageFlags(flags *flag.FlagSet) {
+	flags.StringVar(&logging.Config.Level, "log-level", logging.Config.Level, "minimal log level: debug, info, warn or error")
+	flags.StringVar(&logging.Config.Format, "log-format", logging.Config.Format, "log format: logfmt or json")
+	flags.StringVar(&logging.Config.Path, "log-file", logging.Config.Path, "file to write log to, - for stderr")
+	flags.IntVar(&logging.Config.MaxSize, "log-max-size", logging.Config.MaxSize, "rotate the log file after this many megabytes, 0 to disable")
+	flags.DurationVar(&logging.Config.MaxAge, "log-max-age", logging.Config.MaxAge, "rotate the log file after this time, 0 to disable")
+	flags.IntVar(&logging.Config.Keep, "log-keep", logging.Config.Keep, "number of rotated log files to keep")
+
+	flags.IntVar(&market.Model.Window, "gdr-window", market.Model.Window, "number of daily bars the GDR price is taken over")
+	flags.StringVar(&market.Model.Price, "gdr-price", market.Model.Price, "GDR price over the window: vwap, average or close")
+	flags.StringVar(&market.Model.Session, "gdr-session", market.Model.Session, "today's volume in forecast: auto, intraday or average")
+
+	flags.Float64Var(&valuation.Config.Fee, "fee", valuation.Config.Fee, "broker fee as a share of the sold amount")
+	flags.StringVar(&valuation.Config.Grants, "grants", valuation.Config.Grants, "option grants as count:strike:YYYY-MM-DD, comma separated")
+	flags.StringVar(&valuation.Config.Goals, "goals", valuation.Config.Goals, "value goals in rubles, comma separated")
+
+	return
+}
+
+func main() {
+	packageFlags(flag.CommandLine)
+	flag.Parse()
+	if err := logging.Setup(); err != nil {
+		fmt.Fprintln(os.Stderr, "log setup error:", err)
+	}
+	defer logging.Close()
+
+	locale.Setup(*langOption)
+	if !render.SetTheme(*themeOption) {
+		logging.Warn("unknown theme", "theme", *themeOption)
+	}
+	if err := market.Model.Check(); err != nil {
+		logging.Warn("wrong gdr model", "err", err)
+	}
+	if err := market.ExchangeError(); err != nil {
+		logging.Warn("no zone database, exchange times are in GMT all year", "err", err)
+	}
+	if !market.SetZone(*zoneOption) {
+		logging.Warn("unknown zone", "zone", *zoneOption)
+	}
+	sources := provider.GetSources()
+
+	ctx, cancel := shutdown()
+	defer cancel()
+
+	if flag.NArg() > 0 {
+		code := command(ctx, flag.Args(), sources)
+		cancel()
+		logging.Close()
+		os.Exit(code)
+	}
+
+	served := make(chan struct{})
+	if *httpAddr != "" {
+		go func() {
+			serve(ctx, *httpAddr, sources)
+			close(served)
+		}()
+	} else {
+		close(served)
+	}
+	// the server logs its shutdown, wait for it before the log is closed
+	defer func() {
+		cancel()
+		<-served
+	}()
+
+	if *daemonMode {
+		daemon(ctx, sources)
+		return
+	}
+
+	tui.Run(ctx, sources, tui.Options{
+		Export:       exportOptions,
+		SeriesFormat: *seriesFormat,
+		Publish:      publish,
+	})
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/DKazakov/gdr-go/internal/fixture"
+	"github.com/DKazakov/gdr-go/provider"
+	"github.com/DKazakov/gdr-go/render"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	fixture.Setup()
+
+	os.Exit(m.Run())
+}
+
+// fakeSources returns sources pointed at the fake servers from fixture.
+func fakeSources(t *testing.T) (sources map[string]*provider.Source, close func()) {
+	stockUrl, exchangeUrl, close := fixture.Servers(t)
+
+	stockSaved, exchangeSaved := provider.StockUrl, provider.ExchangeUrl
+	provider.StockUrl, provider.ExchangeUrl = stockUrl, exchangeUrl
+	sources = provider.GetSources()
+	provider.StockUrl, provider.ExchangeUrl = stockSaved, exchangeSaved
+
+	return sources, close
+}
+
+// TestUpdate fetches everything from the fake servers and compares the API
+// view of the result with the golden file.
+func TestUpdate(t *testing.T) {
+	sources, close := fakeSources(t)
+	defer close()
+
+	data, err := provider.Update(context.Background(), sources, provider.Get, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	for name, item := range sources {
+		if stats := item.Snapshot(); stats.LastError != "" || stats.Code != http.StatusOK {
+			t.Errorf("%s: status %d, error %q", name, stats.Code, stats.LastError)
+		}
+	}
+
+	view := new(ApiData).Init(data, nil)
+	view.Sources = nil
+	body, err := json.MarshalIndent(view, "", "\t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixture.Golden(t, "update.json", append(body, '\n'))
+}
+
+// TestCommandFailed runs commands while the chart service is down, they
+// have to fail instead of printing values computed without prices.
+func TestCommandFailed(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+
+	sources, close := fakeSources(t)
+	defer close()
+	for name, item := range sources {
+		if name != "exchange" {
+			item.Url = down.URL
+		}
+	}
+
+	for _, args := range [][]string{{"price"}, {"ladder", "-format", "csv"}, {"exercise"}, {"series", "-dir", os.TempDir()}} {
+		if code := command(context.Background(), args, sources); code != 1 {
+			t.Errorf("%v: exit code %d, want 1", args, code)
+		}
+	}
+}
+
+// TestCommandFormat runs commands with an unknown format, they have to fail
+// as a usage error before fetching anything.
+func TestCommandFormat(t *testing.T) {
+	fetched := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("fetched %s", r.URL)
+		http.Error(w, "unexpected", http.StatusInternalServerError)
+	}))
+	defer fetched.Close()
+
+	sources := provider.GetSources()
+	for _, item := range sources {
+		item.Url = fetched.URL
+	}
+
+	for _, args := range [][]string{{"price", "-format", "xml"}, {"exercise", "-format", "csv"}, {"series", "-format", "json"}, {"export", "-export-format", "gif"}} {
+		if code := command(context.Background(), args, sources); code != 2 {
+			t.Errorf("%v: exit code %d, want 2", args, code)
+		}
+	}
+}
+
+// TestExportDefaults runs the export command after -export-format and
+// -export-dir were given before it.
+func TestExportDefaults(t *testing.T) {
+	sources, close := fakeSources(t)
+	defer close()
+
+	dir, err := ioutil.TempDir("", "gdr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	defer func(saved render.ExportOptions, stdout *os.File) {
+		*exportOptions, os.Stdout = saved, stdout
+	}(*exportOptions, os.Stdout)
+	exportOptions.Dir, exportOptions.Format = dir, "svg"
+	os.Stdout, _ = os.Open(os.DevNull)
+
+	if code := command(context.Background(), []string{"export", "-page", "2"}, sources); code != 0 {
+		t.Fatalf("exit code %d", code)
+	}
+	paths, _ := filepath.Glob(filepath.Join(dir, "gdr-2-*.svg"))
+	if len(paths) != 1 {
+		t.Errorf("%v exported to %s, want one SVG of page 2", paths, dir)
+	}
+}
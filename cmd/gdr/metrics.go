@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/DKazakov/gdr-go/provider"
+	"net/http"
+	"sort"
+)
+
+func metrics(mux *http.ServeMux, sources map[string]*provider.Source) {
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		buffer := bytes.NewBuffer([]byte{})
+
+		if data := current(); data != nil {
+			dprice := data.Gdr * data.LastPrice
+
+			gauge(buffer, "gdr_last_price_usd", "Last traded price of the share.", data.LastPrice)
+			gauge(buffer, "gdr_last_close_usd", "Last closing price of the share.", data.LastClose)
+			gauge(buffer, "gdr_gdr", "Net GDR count on cashless exercise.", data.Gdr)
+			gauge(buffer, "gdr_gdr_forecast", "Forecast of net GDR count for today.", data.GdrForecast)
+			gauge(buffer, "gdr_usd_rub", "USD/RUB exchange rate.", data.Dollar)
+			gauge(buffer, "gdr_option_value_usd", "Total option value in dollars.", dprice)
+			gauge(buffer, "gdr_option_value_rub", "Total option value in rubles.", dprice*data.Dollar)
+		}
+
+		names := []string{}
+		stats := map[string]provider.SourceStats{}
+		for name, item := range sources {
+			names = append(names, name)
+			stats[name] = item.Snapshot()
+		}
+		sort.Strings(names)
+
+		fmt.Fprint(buffer, "# HELP gdr_source_fetch_duration_seconds Duration of the last fetch.\n# TYPE gdr_source_fetch_duration_seconds gauge\n")
+		for _, name := range names {
+			fmt.Fprintf(buffer, "gdr_source_fetch_duration_seconds{source=%q} %g\n", name, stats[name].Duration.Seconds())
+		}
+		fmt.Fprint(buffer, "# HELP gdr_source_errors_total Failed fetches since start.\n# TYPE gdr_source_errors_total counter\n")
+		for _, name := range names {
+			fmt.Fprintf(buffer, "gdr_source_errors_total{source=%q} %d\n", name, stats[name].Errors)
+		}
+		fmt.Fprint(buffer, "# HELP gdr_source_last_success_timestamp_seconds Time of the last successful fetch.\n# TYPE gdr_source_last_success_timestamp_seconds gauge\n")
+		for _, name := range names {
+			var updated float64
+			if !stats[name].Updated.IsZero() {
+				updated = float64(stats[name].Updated.UnixNano()) / 1e9
+			}
+			fmt.Fprintf(buffer, "gdr_source_last_success_timestamp_seconds{source=%q} %g\n", name, updated)
+		}
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		buffer.WriteTo(w)
+	})
+
+	return
+}
+
+func gauge(buffer *bytes.Buffer, name, help string, value float64) {
+	fmt.Fprintf(buffer, "# HELP %s %s\n# TYPE %s gauge\n%s %g\n", name, help, name, name, value)
+
+	return
+}
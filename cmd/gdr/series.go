@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/DKazakov/gdr-go/provider"
+	"os"
+)
+
+func seriesCommand(ctx context.Context, args []string, sources map[string]*provider.Source) int {
+	flags := flag.NewFlagSet("series", flag.ContinueOnError)
+	format := flags.String("format", "csv", "output format: csv or jsonl")
+	dir := flags.String("dir", exportOptions.Dir, "directory to write series to")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if !knownFormat(*format, "csv", "jsonl") {
+		return 2
+	}
+
+	data, ok := fetch(ctx, sources)
+	if !ok {
+		return 1
+	}
+	paths, err := data.ExportSeries(*dir, *format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "export error:", err)
+		return 1
+	}
+
+	for _, path := range paths {
+		fmt.Println(path)
+	}
+
+	return 0
+}
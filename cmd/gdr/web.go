@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/DKazakov/gdr-go/logging"
+	"github.com/DKazakov/gdr-go/render"
+	"github.com/DKazakov/gdr-go/tui"
+	"github.com/DKazakov/gdr-go/valuation"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+const (
+	webChartWidth  = 1200
+	webChartHeight = 600
+)
+
+var (
+	listenersMu sync.Mutex
+	listeners   = map[chan bool]bool{}
+)
+
+type WebView struct {
+	Pages  []string              `json:"pages"`
+	Ladder []valuation.LadderRow `json:"ladder"`
+	Info   []string              `json:"info"`
+}
+
+func subscribe() chan bool {
+	ch := make(chan bool, 1)
+	listenersMu.Lock()
+	listeners[ch] = true
+	listenersMu.Unlock()
+
+	return ch
+}
+func unsubscribe(ch chan bool) {
+	listenersMu.Lock()
+	delete(listeners, ch)
+	listenersMu.Unlock()
+
+	return
+}
+func notify() {
+	listenersMu.Lock()
+	for ch := range listeners {
+		select {
+		case ch <- true:
+		default:
+		}
+	}
+	listenersMu.Unlock()
+
+	return
+}
+
+func dashboard(mux *http.ServeMux) {
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, dashboardPage)
+	})
+
+	mux.HandleFunc("/chart", func(w http.ResponseWriter, r *http.Request) {
+		data := current()
+		if data == nil {
+			http.Error(w, "data is not loaded yet", http.StatusServiceUnavailable)
+			return
+		}
+
+		graph := new(render.Graph).Init(data)
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		graph.SetPage(page)
+
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Cache-Control", "no-cache")
+		graph.Render(webChartWidth, webChartHeight).WriteTo(w)
+	})
+
+	mux.HandleFunc("/api/view", func(w http.ResponseWriter, r *http.Request) {
+		data := current()
+		if data == nil {
+			http.Error(w, "data is not loaded yet", http.StatusServiceUnavailable)
+			return
+		}
+
+		graph := new(render.Graph).Init(data)
+		text := new(tui.Textinfo).Init(data)
+		view := WebView{Ladder: valuation.Ladder(data.LastPrice, data.Dollar, 0, ladderRows), Info: text.InfoLines()}
+		for i := 0; i < graph.PageCount(); i++ {
+			view.Pages = append(view.Pages, graph.PageName(i))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(view); err != nil {
+			logging.Error("view encoding error", "err", err)
+		}
+	})
+
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+			return
+		}
+
+		ch := subscribe()
+		defer unsubscribe(ch)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		flusher.Flush()
+
+		for {
+			select {
+			case <-ch:
+				fmt.Fprint(w, "event: update\ndata: {}\n\n")
+				flusher.Flush()
+			case <-r.Context().Done():
+				return
+			}
+		}
+	})
+
+	return
+}
+
+const dashboardPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>gdr</title>
+<style>
+body { margin: 0; font: 13px monospace; display: flex; flex-direction: column; height: 100vh; }
+#main { display: flex; flex: 1; min-height: 0; }
+#ladder { overflow-y: auto; padding-right: 8px; white-space: pre; }
+#ladder div.odd { background: #6c6c6c; }
+#ladder div.current { background: #00af00; }
+#ladder div.goal { background: #ff0000; }
+#chart { flex: 1; display: flex; flex-direction: column; align-items: center; min-width: 0; }
+#chart img { max-width: 100%; max-height: 100%; }
+#pages span { cursor: pointer; padding: 0 6px; }
+#pages span.active { font-weight: bold; text-decoration: underline; }
+#info { white-space: pre; padding: 4px 0; }
+</style>
+</head>
+<body>
+<div id="main">
+<div id="ladder"></div>
+<div id="chart"><img id="image"><div id="pages"></div></div>
+</div>
+<div id="info"></div>
+<script>
+var page = 0;
+
+function chart() {
+	document.getElementById("image").src = "/chart?page=" + page + "&t=" + Date.now();
+}
+function ranges(value) {
+	return Math.floor(value).toString().replace(/\B(?=(\d{3})+(?!\d))/g, ",");
+}
+function view() {
+	fetch("/api/view").then(function(r) { return r.json(); }).then(function(v) {
+		var ladder = document.getElementById("ladder");
+		ladder.innerHTML = "";
+		v.ladder.forEach(function(row) {
+			var div = document.createElement("div");
+			div.className = row.kind;
+			div.textContent = row.price.toFixed(2) + ": " + ranges(row.value) + "  " + ranges(row.rvalue);
+			ladder.appendChild(div);
+		});
+
+		var pages = document.getElementById("pages");
+		pages.innerHTML = "";
+		v.pages.forEach(function(name, i) {
+			var span = document.createElement("span");
+			span.textContent = name;
+			span.className = i == page ? "active" : "";
+			span.onclick = function() { page = i; chart(); view(); };
+			pages.appendChild(span);
+		});
+
+		document.getElementById("info").textContent = v.info.join("\n");
+	});
+	chart();
+}
+
+view();
+new EventSource("/events").addEventListener("update", view);
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,11 @@
+module github.com/DKazakov/gdr-go
+
+go 1.13
+
+require (
+	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0 // indirect
+	github.com/mattn/go-runewidth v0.0.9
+	github.com/nsf/termbox-go v1.1.1
+	github.com/wcharczuk/go-chart v2.0.1+incompatible
+	golang.org/x/image v0.18.0 // indirect
+)
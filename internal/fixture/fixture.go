@@ -0,0 +1,160 @@
+// Package fixture gives tests recorded responses from testdata, market data
+// built from them, fake servers that return them and golden files.
+package fixture
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"github.com/DKazakov/gdr-go/logging"
+	"github.com/DKazakov/gdr-go/market"
+	"github.com/DKazakov/gdr-go/provider"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+var Update = flag.Bool("update", false, "rewrite golden files in testdata/golden")
+
+// Setup is for TestMain: it parses the test flags, keeps the log in memory
+// and fixes the local zone, one of the display zones, to UTC so the output
+// is the same on every machine.
+func Setup() {
+	flag.Parse()
+	time.Local = time.UTC
+	logging.Detach()
+
+	return
+}
+
+// Path returns name in testdata at the root of the module, so tests of
+// every package share the fixtures.
+func Path(name string) string {
+	_, file, _, _ := runtime.Caller(0)
+
+	return filepath.Join(filepath.Dir(file), "..", "..", "testdata", name)
+}
+
+func Read(t *testing.T, name string) []byte {
+	body, err := ioutil.ReadFile(Path(name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return body
+}
+func JSON(t *testing.T, name string, value interface{}) {
+	if err := json.Unmarshal(Read(t, name), value); err != nil {
+		t.Fatal(err)
+	}
+
+	return
+}
+
+// Stock decodes a recorded chart service or exchange rate response.
+func Stock(t *testing.T, name string) *provider.JsonStock {
+	stock := new(provider.JsonStock)
+	JSON(t, name, stock)
+
+	return stock
+}
+
+// Data is finalized market data from all recorded responses, as after an
+// update.
+func Data(t *testing.T) *market.Data {
+	data := new(market.Data).Init()
+	data.Set("days", provider.DaysCallback(Stock(t, "lse_days.json")))
+	data.Set("weeks", provider.WeeksCallback(Stock(t, "lse_weeks.json")))
+	data.Set("hours", provider.HoursCallback(Stock(t, "lse_hours.json")))
+	data.Set("exchange", provider.ExchangeCallback(Stock(t, "fx.json")))
+	data.Finalize()
+
+	return data
+}
+
+// Servers stand in for the LSE chart service and the exchange rate API and
+// return their addresses in the form the sources are configured with.
+func Servers(t *testing.T) (stockUrl, exchangeUrl string, close func()) {
+	fixtures := map[string]string{
+		"1d/1y":  "lse_days.json",
+		"1d/5y":  "lse_weeks.json",
+		"1mm/1d": "lse_hours.json",
+	}
+
+	stock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request struct {
+				SampleTime, TimeFrame string
+				OffSet                int
+			} `json:"request"`
+		}
+		// the recorded times are shifted by the hour OffSet -60 asks for
+		if r.Method != "POST" || json.NewDecoder(r.Body).Decode(&body) != nil || body.Request.OffSet != -60 {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		name, ok := fixtures[body.Request.SampleTime+"/"+body.Request.TimeFrame]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(Read(t, name))
+	}))
+	exchange := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("symbols") != "RUB,USD" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		w.Write(Read(t, "fx.json"))
+	}))
+
+	return stock.URL, exchange.URL + "/latest?symbols=RUB,USD", func() {
+		stock.Close()
+		exchange.Close()
+	}
+}
+
+// Golden compares got with testdata/golden/name, -update rewrites the file.
+func Golden(t *testing.T, name string, got []byte) {
+	want, ok := GoldenFile(t, name, got)
+	if !ok {
+		return
+	}
+	if want == nil {
+		t.Fatalf("%s is missing, run go test -update to create it", Path(filepath.Join("golden", name)))
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s differs from the golden file, run go test -update if the change is expected\ngot:\n%s", name, got)
+	}
+
+	return
+}
+
+// GoldenFile reads testdata/golden/name, nil if it is missing, or writes got
+// to it with -update, then there is nothing to compare.
+func GoldenFile(t *testing.T, name string, got []byte) ([]byte, bool) {
+	path := Path(filepath.Join("golden", name))
+	if *Update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return nil, false
+	}
+
+	want, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, true
+	} else if err != nil {
+		t.Fatal(err)
+	}
+
+	return want, true
+}
@@ -0,0 +1,206 @@
+// Package locale keeps interface messages and number and date formats in
+// Russian and English.
+package locale
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type Locale struct {
+	Group, Point              string
+	Months, Days, Hours, Date string
+	Messages                  map[string]string
+}
+
+var (
+	Locales = map[string]*Locale{
+		"ru": {
+			Group:  " ",
+			Point:  ",",
+			Months: "01.2006",
+			Days:   "02.01",
+			Hours:  "15:04",
+			Date:   "02.01.2006",
+			Messages: map[string]string{
+				"page.today":             "сегодня",
+				"page.month":             "за последний месяц",
+				"page.year":              "за последний год",
+				"page.fiveyears":         "за пять лет",
+				"page.projection":        "прогноз стоимости",
+				"label.price":            "цена, макс: %s, мин: %s, последняя: %s",
+				"label.volume":           "объём в масштабе, макс: %skk, мин: %skk",
+				"label.gdr":              "GDR в масштабе, макс: %s, мин: %s",
+				"label.current":          "текущая цена %s",
+				"label.close":            "последнее закрытие %s",
+				"info.price":             "Стоимость сейчас: %s %s Последнее обновление %s, последняя попытка %s",
+				"info.gdr":               "GDR: %s (прогноз: %s => %s рублей)",
+				"info.total":             "Общая стоимость: %s доллара (%s рублей при курсе %s)",
+				"scenario.head":          "Сценарий: цена %s (сейчас %s), курс %s (сейчас %s)",
+				"scenario.options":       "Опционы: %s $ / %s руб., после налога %s руб. (налог %s) | сейчас: %s $ / %s руб., после налога %s руб.",
+				"scenario.gdr":           "GDR: %s => %s $ / %s руб. | сейчас: %s => %s $ / %s руб.",
+				"breakdown.formula":      "GDR = N - N * strike / цена",
+				"breakdown.model":        "окно: %d, цена: %s",
+				"breakdown.gdr":          "GDR:",
+				"breakdown.forecast":     "Прогноз:",
+				"breakdown.today":        "сегодня",
+				"breakdown.volume":       "объём сегодня: %s",
+				"breakdown.intraday":     "внутридневной",
+				"breakdown.average":      "средний за месяц",
+				"exercise.head":          "Цена %s, курс %s:",
+				"exercise.none":          "опционы без выгоды",
+				"exercise.shares":        "%s: акций %s, оплата %s $, деньги %s $",
+				"exercise.fees":          "комиссия %s $, налог %s руб., итого %s руб.",
+				"exercise.cash":          "кэш",
+				"exercise.sell-to-cover": "продажа на покрытие",
+				"exercise.same-day sale": "продажа всего",
+				"projection.summary":     "к %s медиана %sk руб.",
+				"projection.percentile":  "%s: %sk руб.",
+				"projection.goal":        "цель %sk руб.: %s",
+				"projection.grant":       "%s: %s по %s, медиана %sk руб.",
+				"cursor.value":           "GDR %s  опционы %s $ / %s руб.",
+				"prompt.range":           "период (дд.мм.гггг-дд.мм.гггг): ",
+				"prompt.price":           "цена (пусто - текущая, Esc - сброс): ",
+				"prompt.dollar":          "курс (пусто - текущий): ",
+				"notice.saved":           "сохранено: %s",
+				"notice.saved_charts":    "сохранено графиков: %d",
+				"notice.saved_series":    "сохранено рядов: %d",
+				"notice.save_error":      "ошибка сохранения",
+				"notice.wrong_range":     "неверный период",
+				"notice.wrong_values":    "неверные значения",
+				"log.source":             "%-8s %-8s код %3d  успех %s  %4d мс  %s байт",
+				"log.waiting":            "ожидание",
+				"log.done":               "готово",
+				"log.error":              "ошибка",
+			},
+		},
+		"en": {
+			Group:  ",",
+			Point:  ".",
+			Months: "Jan 2006",
+			Days:   "Jan 02",
+			Hours:  "15:04",
+			Date:   "01/02/2006",
+			Messages: map[string]string{
+				"page.today":             "today",
+				"page.month":             "last month",
+				"page.year":              "last year",
+				"page.fiveyears":         "five years",
+				"page.projection":        "value projection",
+				"label.price":            "price, max: %s, min: %s, last: %s",
+				"label.volume":           "scaled value, max: %skk, min: %skk",
+				"label.gdr":              "scaled GDR's, max: %s, min: %s",
+				"label.current":          "current price %s",
+				"label.close":            "last closing price %s",
+				"info.price":             "Price now: %s %s Last update %s, last attempt %s",
+				"info.gdr":               "GDR: %s (forecast: %s => %s rubles)",
+				"info.total":             "Total value: %s dollars (%s rubles at %s)",
+				"scenario.head":          "Scenario: price %s (now %s), rate %s (now %s)",
+				"scenario.options":       "Options: %s $ / %s RUB, after tax %s RUB (tax %s) | now: %s $ / %s RUB, after tax %s RUB",
+				"scenario.gdr":           "GDR: %s => %s $ / %s RUB | now: %s => %s $ / %s RUB",
+				"breakdown.formula":      "GDR = N - N * strike / price",
+				"breakdown.model":        "window: %d, price: %s",
+				"breakdown.gdr":          "GDR:",
+				"breakdown.forecast":     "Forecast:",
+				"breakdown.today":        "today",
+				"breakdown.volume":       "today's volume: %s",
+				"breakdown.intraday":     "intraday",
+				"breakdown.average":      "monthly average",
+				"exercise.head":          "Price %s, rate %s:",
+				"exercise.none":          "options are out of the money",
+				"exercise.shares":        "%s: shares %s, paid %s $, cash %s $",
+				"exercise.fees":          "fees %s $, tax %s RUB, net %s RUB",
+				"exercise.cash":          "cash exercise",
+				"exercise.sell-to-cover": "sell to cover",
+				"exercise.same-day sale": "same-day sale",
+				"projection.summary":     "by %s median %sk RUB",
+				"projection.percentile":  "%s: %sk RUB",
+				"projection.goal":        "goal %sk RUB: %s",
+				"projection.grant":       "%s: %s at %s, median %sk RUB",
+				"cursor.value":           "GDR %s  options %s $ / %s RUB",
+				"prompt.range":           "range (mm/dd/yyyy-mm/dd/yyyy): ",
+				"prompt.price":           "price (empty - current, Esc - reset): ",
+				"prompt.dollar":          "rate (empty - current): ",
+				"notice.saved":           "saved: %s",
+				"notice.saved_charts":    "charts saved: %d",
+				"notice.saved_series":    "series saved: %d",
+				"notice.save_error":      "saving failed",
+				"notice.wrong_range":     "wrong range",
+				"notice.wrong_values":    "wrong values",
+				"log.source":             "%-8s %-8s code %3d  success %s  %4d ms  %s bytes",
+				"log.waiting":            "waiting",
+				"log.done":               "done",
+				"log.error":              "error",
+			},
+		},
+	}
+	current = Locales["ru"]
+)
+
+// Setup picks the locale by name, or from LC_ALL, LC_MESSAGES or LANG if
+// name is empty. Unknown languages leave Russian.
+func Setup(name string) {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if name != "" {
+			break
+		}
+		name = os.Getenv(env)
+	}
+
+	if len(name) >= 2 {
+		Set(name[:2])
+	}
+
+	return
+}
+func Set(name string) bool {
+	item, ok := Locales[strings.ToLower(name)]
+	if ok {
+		current = item
+	}
+
+	return ok
+}
+func Current() *Locale {
+	return current
+}
+
+func Tr(key string, args ...interface{}) string {
+	message, ok := current.Messages[key]
+	if !ok {
+		message, ok = Locales["ru"].Messages[key]
+	}
+	if !ok {
+		message = key
+	}
+
+	return fmt.Sprintf(message, args...)
+}
+func Number(i float64) string {
+	return current.Number(i)
+}
+func Float(i float64, precision int) string {
+	return current.Float(i, precision)
+}
+
+// Number formats the integer part of i with thousands grouped.
+func (self *Locale) Number(i float64) string {
+	var (
+		out  = ""
+		sign = ""
+	)
+	if i < 0 {
+		sign = "-"
+		i = -i
+	}
+	for ; i >= 1000.0; i = i / 1000.0 {
+		out = fmt.Sprintf("%s%03d", self.Group, int(i)%1000) + out
+	}
+
+	return fmt.Sprintf("%s%d%s", sign, int(i), out)
+}
+func (self *Locale) Float(i float64, precision int) string {
+	return strings.Replace(strconv.FormatFloat(i, 'f', precision, 64), ".", self.Point, 1)
+}
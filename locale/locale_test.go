@@ -0,0 +1,47 @@
+package locale
+
+import "testing"
+
+func TestNumber(t *testing.T) {
+	tests := []struct {
+		locale string
+		value  float64
+		want   string
+	}{
+		{"ru", 0, "0"},
+		{"ru", 999.9, "999"},
+		{"ru", 1000, "1 000"},
+		{"ru", 1005, "1 005"},
+		{"ru", 1234567.8, "1 234 567"},
+		{"ru", -1234, "-1 234"},
+		{"en", 1234567, "1,234,567"},
+		{"en", 100000, "100,000"},
+	}
+
+	for _, test := range tests {
+		if got := Locales[test.locale].Number(test.value); got != test.want {
+			t.Errorf("%s number(%v) = %q, want %q", test.locale, test.value, got, test.want)
+		}
+	}
+}
+
+func TestFloat(t *testing.T) {
+	if got := Locales["ru"].Float(19.6, 2); got != "19,60" {
+		t.Errorf("ru float = %q", got)
+	}
+	if got := Locales["en"].Float(-0.125, 1); got != "-0.1" {
+		t.Errorf("en float = %q", got)
+	}
+}
+
+func TestTr(t *testing.T) {
+	defer func(saved *Locale) { current = saved }(current)
+
+	Set("en")
+	if got := Tr("notice.saved_charts", 3); got != "charts saved: 3" {
+		t.Errorf("tr = %q", got)
+	}
+	if got := Tr("no.such.key"); got != "no.such.key" {
+		t.Errorf("tr of a missing key = %q", got)
+	}
+}
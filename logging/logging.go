@@ -0,0 +1,337 @@
+// Package logging writes levelled logfmt or JSON records to a rotating file
+// and keeps the last lines for the terminal.
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	levelDebug = iota
+	levelInfo
+	levelWarn
+	levelError
+)
+
+// Options set up the logger, Config is used by Setup.
+type Options struct {
+	Level   string
+	Format  string
+	Path    string
+	MaxSize int
+	MaxAge  time.Duration
+	Keep    int
+}
+
+var (
+	levelNames = []string{"debug", "info", "warn", "error"}
+	Config     = Options{
+		Level:   "info",
+		Format:  "logfmt",
+		Path:    "/var/log/self/gdr.log",
+		MaxSize: 10,
+		MaxAge:  7 * 24 * time.Hour,
+		Keep:    5,
+	}
+	logger = &Logger{level: levelInfo, format: "logfmt", out: os.Stderr}
+	// time of a logfmt or JSON record at the start of a file
+	firstRecord = regexp.MustCompile(`^(?:time=|\{"time":")([^ "]+)`)
+)
+
+// Logger writes one line per record: time, level, message and key-value
+// fields, either as logfmt or as a JSON object.
+type Logger struct {
+	mu     sync.Mutex
+	level  int
+	format string
+	out    io.Writer
+	file   *RotatingFile
+}
+
+// Setup configures the logger from Config. The log also goes to Tail shown
+// in the terminal, and the standard log package is routed through it.
+func Setup() (err error) {
+	var out io.Writer = os.Stderr
+
+	level := parseLevel(Config.Level)
+	if level < 0 {
+		err = fmt.Errorf("unknown log level %q", Config.Level)
+		level = levelInfo
+	}
+	if Config.Format != "logfmt" && Config.Format != "json" {
+		err = fmt.Errorf("unknown log format %q", Config.Format)
+		Config.Format = "logfmt"
+	}
+
+	if Config.Path != "-" && Config.Path != "stderr" {
+		file, openErr := openRotating(Config.Path, int64(Config.MaxSize)<<20, Config.MaxAge, Config.Keep)
+		if openErr != nil {
+			err = openErr
+		} else {
+			logger.file = file
+			out = file
+		}
+	}
+
+	logger.mu.Lock()
+	logger.level = level
+	logger.format = Config.Format
+	logger.out = io.MultiWriter(out, Tail)
+	logger.mu.Unlock()
+
+	log.SetFlags(0)
+	log.SetOutput(logger)
+
+	return err
+}
+func Close() {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+
+	if logger.file != nil {
+		logger.file.Close()
+		logger.file = nil
+		logger.out = Tail
+	}
+
+	return
+}
+
+// Detach keeps the log only in Tail, for when stderr is the terminal.
+func Detach() {
+	logger.mu.Lock()
+	logger.out = Tail
+	logger.mu.Unlock()
+
+	return
+}
+func ToFile() bool {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+
+	return logger.file != nil
+}
+func parseLevel(name string) int {
+	for i, item := range levelNames {
+		if strings.EqualFold(item, name) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func Debug(msg string, fields ...interface{}) {
+	logger.log(levelDebug, msg, fields...)
+}
+func Info(msg string, fields ...interface{}) {
+	logger.log(levelInfo, msg, fields...)
+}
+func Warn(msg string, fields ...interface{}) {
+	logger.log(levelWarn, msg, fields...)
+}
+func Error(msg string, fields ...interface{}) {
+	logger.log(levelError, msg, fields...)
+}
+
+// Write takes messages of the standard log package as warnings.
+func (self *Logger) Write(p []byte) (int, error) {
+	self.log(levelWarn, strings.TrimRight(string(p), "\n"))
+
+	return len(p), nil
+}
+
+func (self *Logger) log(level int, msg string, fields ...interface{}) {
+	if level < self.level {
+		return
+	}
+
+	keys := []string{"time", "level", "msg"}
+	values := []interface{}{time.Now().Format(time.RFC3339Nano), levelNames[level], msg}
+	for i := 0; i+1 < len(fields); i = i + 2 {
+		keys = append(keys, fmt.Sprint(fields[i]))
+		values = append(values, fieldValue(fields[i+1]))
+	}
+	if len(fields)%2 == 1 {
+		keys = append(keys, "extra")
+		values = append(values, fieldValue(fields[len(fields)-1]))
+	}
+
+	buffer := bytes.NewBuffer([]byte{})
+	if self.format == "json" {
+		jsonLine(buffer, keys, values)
+	} else {
+		logfmtLine(buffer, keys, values)
+	}
+	buffer.WriteByte('\n')
+
+	self.mu.Lock()
+	self.out.Write(buffer.Bytes())
+	self.mu.Unlock()
+
+	return
+}
+func fieldValue(value interface{}) interface{} {
+	switch item := value.(type) {
+	case error:
+		return item.Error()
+	case time.Duration:
+		return item.String()
+	case time.Time:
+		return item.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return item.String()
+	}
+
+	return value
+}
+
+func jsonLine(buffer *bytes.Buffer, keys []string, values []interface{}) {
+	buffer.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		name, _ := json.Marshal(key)
+		value, err := json.Marshal(values[i])
+		if err != nil {
+			value, _ = json.Marshal(fmt.Sprint(values[i]))
+		}
+		buffer.Write(name)
+		buffer.WriteByte(':')
+		buffer.Write(value)
+	}
+	buffer.WriteByte('}')
+
+	return
+}
+func logfmtLine(buffer *bytes.Buffer, keys []string, values []interface{}) {
+	for i, key := range keys {
+		if i > 0 {
+			buffer.WriteByte(' ')
+		}
+		value := fmt.Sprint(values[i])
+		if value == "" || strings.ContainsAny(value, " =\"\t\n") {
+			value = strconv.Quote(value)
+		}
+		buffer.WriteString(key + "=" + value)
+	}
+
+	return
+}
+
+// RotatingFile is a log file that is moved to path.1, path.2 and so on when
+// it grows over maxSize or was created longer than maxAge ago.
+type RotatingFile struct {
+	path    string
+	maxSize int64
+	maxAge  time.Duration
+	keep    int
+	file    *os.File
+	size    int64
+	opened  time.Time
+}
+
+func openRotating(path string, maxSize int64, maxAge time.Duration, keep int) (*RotatingFile, error) {
+	self := &RotatingFile{path: path, maxSize: maxSize, maxAge: maxAge, keep: keep}
+	if err := self.open(); err != nil {
+		return nil, err
+	}
+
+	return self, nil
+}
+func (self *RotatingFile) open() error {
+	file, err := os.OpenFile(self.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	self.file = file
+	self.size = info.Size()
+	self.opened = time.Now()
+	if self.size > 0 {
+		self.opened = created(self.path, info.ModTime())
+	}
+
+	return nil
+}
+
+// created is the time of the first record in the log at path, so the age
+// of a file appended to after a restart counts from its start. Without a
+// record it is modified.
+func created(path string, modified time.Time) time.Time {
+	file, err := os.Open(path)
+	if err != nil {
+		return modified
+	}
+	defer file.Close()
+
+	head := make([]byte, 64)
+	n, _ := io.ReadFull(file, head)
+	match := firstRecord.FindSubmatch(head[:n])
+	if match == nil {
+		return modified
+	}
+	at, err := time.Parse(time.RFC3339Nano, string(match[1]))
+	if err != nil {
+		return modified
+	}
+
+	return at
+}
+
+func (self *RotatingFile) Write(p []byte) (int, error) {
+	if self.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if (self.maxSize > 0 && self.size+int64(len(p)) > self.maxSize) || (self.maxAge > 0 && time.Since(self.opened) > self.maxAge) {
+		if err := self.rotate(); err != nil {
+			fmt.Fprintln(os.Stderr, "log rotation error:", err)
+		}
+	}
+
+	n, err := self.file.Write(p)
+	self.size = self.size + int64(n)
+
+	return n, err
+}
+func (self *RotatingFile) rotate() error {
+	self.file.Close()
+
+	os.Remove(fmt.Sprintf("%s.%d", self.path, self.keep))
+	for i := self.keep - 1; i > 0; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", self.path, i), fmt.Sprintf("%s.%d", self.path, i+1))
+	}
+	if self.keep > 0 {
+		os.Rename(self.path, self.path+".1")
+	} else {
+		os.Remove(self.path)
+	}
+
+	return self.open()
+}
+func (self *RotatingFile) Close() error {
+	if self.file == nil {
+		return nil
+	}
+	err := self.file.Close()
+	self.file = nil
+
+	return err
+}
@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestRotateByAge appends to logs left by an earlier run, the age counts
+// from their first record and not from the restart.
+func TestRotateByAge(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gdr-log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name, first string
+		rotated     bool
+	}{
+		{"old.log", "time=%s level=info msg=started\n", true},
+		{"old.json", `{"time":"%s","level":"info","msg":"started"}` + "\n", true},
+		{"fresh.log", "time=%s level=info msg=started\n", false},
+	}
+
+	for _, test := range tests {
+		age := 10 * 24 * time.Hour
+		if !test.rotated {
+			age = time.Hour
+		}
+		path := filepath.Join(dir, test.name)
+		first := fmt.Sprintf(test.first, time.Now().Add(-age).Format(time.RFC3339Nano))
+		if err := ioutil.WriteFile(path, []byte(first), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		file, err := openRotating(path, 0, 7*24*time.Hour, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		file.Write([]byte("time=now level=info msg=restarted\n"))
+		file.Close()
+
+		_, err = os.Stat(path + ".1")
+		if rotated := err == nil; rotated != test.rotated {
+			t.Errorf("%s: rotated %v, want %v", test.name, rotated, test.rotated)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"strings"
+	"sync"
+)
+
+const logTailSize = 200
+
+// LogTail keeps the last log lines to show them in the terminal.
+type LogTail struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+var Tail = new(LogTail)
+
+func (self *LogTail) Write(p []byte) (int, error) {
+	self.mu.Lock()
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		self.lines = append(self.lines, line)
+	}
+	if len(self.lines) > logTailSize {
+		self.lines = self.lines[len(self.lines)-logTailSize:]
+	}
+	self.mu.Unlock()
+
+	return len(p), nil
+}
+
+// Lines returns count lines ending skip lines before the last one.
+func (self *LogTail) Lines(count, skip int) []string {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if count < 0 {
+		count = 0
+	}
+	end := len(self.lines) - skip
+	if end < 0 {
+		end = 0
+	}
+	begin := end - count
+	if begin < 0 {
+		begin = 0
+	}
+
+	return append([]string{}, self.lines[begin:end]...)
+}
+func (self *LogTail) Len() int {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	return len(self.lines)
+}
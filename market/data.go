@@ -0,0 +1,307 @@
+// Package market is the data model: price series with volumes and GDR
+// values, and the current state derived from them.
+package market
+
+import (
+	"fmt"
+	"github.com/DKazakov/gdr-go/locale"
+	"github.com/DKazakov/gdr-go/valuation"
+	"time"
+)
+
+var PageKeys = []string{"page.today", "page.month", "page.year", "page.fiveyears", "page.projection"}
+
+type GraphDataLabels struct {
+	X, XV, XGdr, Waterline string
+}
+type Extremum struct {
+	X, XV, XGdr, Chart float64
+}
+
+// GraphData is a series of prices X at times Y in the exchange zone, with
+// daily volumes and GDR values as they are and scaled to the price range.
+type GraphData struct {
+	Name                     string
+	X, Volume, XV, Gdr, XGdr []float64
+	Y                        []time.Time
+	Waterline                float64
+	Labels                   *GraphDataLabels
+	Maximum, Minimum         *Extremum
+	ValueFormatter           func(interface{}) string
+	Ohlc                     [][4]float64
+}
+
+func (self *GraphData) SetValues(y time.Time, x ...float64) {
+	var prev float64
+	next := x[0]
+	lenx := len(self.X)
+
+	if lenx > 0 {
+		prev = self.X[lenx-1]
+		if next > prev*2 {
+			next = prev
+		}
+	}
+
+	self.Y = append(self.Y, y)
+	self.X = append(self.X, next)
+
+	if len(x) > 1 {
+		self.Volume = append(self.Volume, x[1])
+	}
+}
+func (self *GraphData) SetOhlc(e []float64) {
+	if len(e) > 4 {
+		self.Ohlc = append(self.Ohlc, [4]float64{e[1], e[2], e[3], e[4]})
+	}
+
+	return
+}
+func (self *GraphData) setExtremum() {
+	var (
+		min = new(Extremum)
+		max = new(Extremum)
+	)
+
+	min.X, max.X = MinMax(self.X)
+	if len(self.Volume) > 0 {
+		min.XV, max.XV = MinMax(self.Volume)
+	}
+	if len(self.Gdr) > 0 {
+		min.XGdr, max.XGdr = MinMax(self.Gdr)
+	}
+	delta := (max.X - min.X) * 0.01
+	min.Chart, max.Chart = MinMax([]float64{max.X + delta, min.X - delta, self.Waterline + 0.2, self.Waterline - 0.2})
+	self.Maximum = max
+	self.Minimum = min
+
+	return
+}
+func (self *GraphData) SetGdr(gdr float64) {
+	if gdr > 0 {
+		self.Gdr = append(self.Gdr, gdr)
+	}
+
+	return
+}
+func (self GraphData) GetGdr(next ...float64) (gdr float64) {
+	return self.GdrBreakdown(next...).Gdr
+}
+
+func (self *GraphData) Finalize(waterline float64, formatType string) {
+	self.Waterline = waterline
+	self.setExtremum()
+	labels := new(GraphDataLabels)
+
+	if len(self.X) > 0 {
+		labels.X = locale.Tr("label.price", locale.Float(self.Maximum.X, 2), locale.Float(self.Minimum.X, 2), locale.Float(self.X[len(self.X)-1], 2))
+	}
+
+	if len(self.Volume) > 0 {
+		C := (self.Maximum.X - self.Minimum.X) / (self.Maximum.XV - self.Minimum.XV)
+		for _, e := range self.Volume {
+			self.XV = append(self.XV, self.Minimum.X+((e-self.Minimum.XV)*C))
+		}
+		labels.XV = locale.Tr("label.volume", locale.Float(self.Maximum.XV/1000000, 3), locale.Float(self.Minimum.XV/1000000, 3))
+	}
+	if len(self.Gdr) > 0 {
+		C := (self.Maximum.X - self.Minimum.X) / (self.Maximum.XGdr - self.Minimum.XGdr)
+		for _, e := range self.Gdr {
+			self.XGdr = append(self.XGdr, self.Minimum.X+((e-self.Minimum.XGdr)*C))
+		}
+		labels.XGdr = locale.Tr("label.gdr", locale.Float(self.Maximum.XGdr, 2), locale.Float(self.Minimum.XGdr, 2))
+	}
+
+	labels.Waterline = locale.Tr("label.current", locale.Float(waterline, 2))
+
+	self.ValueFormatter = DaysValueFormatter
+	if formatType == "hours" {
+		self.ValueFormatter = HoursValueFormatter
+		labels.Waterline = locale.Tr("label.close", locale.Float(waterline, 2))
+	} else if formatType == "months" {
+		self.ValueFormatter = MonthsValueFormatter
+	}
+	self.Labels = labels
+
+	return
+}
+
+func MonthsValueFormatter(v interface{}) string {
+	typed := v.(float64)
+	return Display(unstamp(typed)).Format(locale.Current().Months)
+}
+func DaysValueFormatter(v interface{}) string {
+	typed := v.(float64)
+	return Display(unstamp(typed)).Format(locale.Current().Days)
+}
+func HoursValueFormatter(v interface{}) string {
+	typed := v.(float64)
+	return Display(unstamp(typed)).Format(locale.Current().Hours)
+}
+
+func MinMax(array []float64) (min float64, max float64) {
+	if len(array) > 0 {
+		min = array[0]
+		max = array[0]
+		for _, e := range array {
+			if max < e {
+				max = e
+			}
+			if min > e {
+				min = e
+			}
+		}
+	}
+
+	return
+}
+
+// Data is everything fetched at once: today, month, year and five years
+// pages, the history combined from them and the values derived.
+type Data struct {
+	Graph                [4]GraphData
+	History              GraphData
+	Gdr, GdrForecast     float64
+	LastPrice, LastClose float64
+	LastUpdate           time.Time
+	Dollar               float64
+	Projection           *valuation.Projection
+	GdrToday, GdrNext    GdrBreakdown
+}
+
+func (self *Data) Init() *Data {
+	self = new(Data)
+
+	return self
+}
+
+func (self *Data) Set(name string, data []GraphData) {
+	switch name {
+	case "days":
+		data[0].Name = locale.Tr(PageKeys[1])
+		self.Graph[1] = data[0]
+		data[1].Name = locale.Tr(PageKeys[2])
+		self.Graph[2] = data[1]
+	case "weeks":
+		data[0].Name = locale.Tr(PageKeys[3])
+		self.Graph[3] = data[0]
+	case "hours":
+		data[0].Name = locale.Tr(PageKeys[0])
+		self.Graph[0] = data[0]
+	case "exchange":
+		self.Dollar = data[0].X[0]
+	default:
+		break
+	}
+}
+
+func (self *Data) Finalize() int {
+	if len(self.Graph[1].X) > 0 {
+		self.LastClose = self.Graph[1].X[len(self.Graph[1].X)-1]
+	} else {
+		self.LastClose = 0
+	}
+	if len(self.Graph[0].X) > 0 {
+		self.LastPrice = self.Graph[0].X[len(self.Graph[0].X)-1]
+		self.LastUpdate = self.Graph[0].Y[len(self.Graph[0].Y)-1]
+	} else {
+		self.LastPrice = self.LastClose
+		self.LastUpdate = time.Unix(0, 0)
+	}
+
+	self.combine()
+	self.History.Waterline = self.LastPrice
+
+	self.Graph[0].Finalize(self.LastClose, "hours")
+	self.Graph[1].Finalize(self.LastPrice, "days")
+	self.Graph[2].Finalize(self.LastPrice, "days")
+	self.Graph[3].Finalize(self.LastPrice, "months")
+
+	if len(self.Graph[1].Gdr) > 0 {
+		self.Gdr = self.Graph[1].Gdr[len(self.Graph[1].Gdr)-1]
+	}
+
+	self.GdrToday = self.Graph[2].GdrBreakdown()
+	volume, session := self.ForecastVolume()
+	self.GdrNext = self.Graph[1].GdrBreakdown(self.LastPrice, volume)
+	self.GdrNext.Session = session
+	self.GdrForecast = self.GdrNext.Gdr
+	self.Projection = new(valuation.Projection).Init(self.Graph[3].Y, self.Graph[3].X, self.LastPrice, self.Dollar, valuation.GetGrants(), valuation.GetGoals())
+
+	return len(self.Graph)
+}
+
+func (self *Data) combine() {
+	var (
+		history                        GraphData
+		yearBegin, monthBegin, yearEnd time.Time
+	)
+
+	if len(self.Graph[2].Y) > 0 {
+		yearBegin = self.Graph[2].Y[0]
+		yearEnd = self.Graph[2].Y[len(self.Graph[2].Y)-1]
+	}
+	monthBegin = yearEnd
+	if len(self.Graph[1].Y) > 0 {
+		monthBegin = self.Graph[1].Y[0]
+	}
+	// the last month is taken from its own page, which has GDR values
+	history.add(self.Graph[3], time.Time{}, yearBegin)
+	history.add(self.Graph[2], yearBegin, monthBegin.Add(-time.Nanosecond))
+	history.add(self.Graph[1], monthBegin, yearEnd)
+	history.add(self.Graph[0], yearEnd, time.Time{})
+	self.History = history
+
+	return
+}
+
+// add copies points of item with time in [from, to], zero from or to
+// leave the range open. OHLC is kept only while every added series has it.
+// Volumes and GDR values stay aligned to the end, so a point without volume
+// drops the volumes before it, and a point without GDR takes the last one,
+// as GDR is known per day and intraday points have none.
+func (self *GraphData) add(item GraphData, from, to time.Time) {
+	for i, y := range item.Y {
+		if (!from.IsZero() && y.Before(from)) || (!to.IsZero() && y.After(to)) || (len(self.Y) > 0 && !y.After(self.Y[len(self.Y)-1])) {
+			continue
+		}
+		if len(self.Ohlc) == len(self.Y) && len(item.Ohlc) == len(item.Y) {
+			self.Ohlc = append(self.Ohlc, item.Ohlc[i])
+		}
+		self.Y = append(self.Y, y)
+		self.X = append(self.X, item.X[i])
+
+		self.Volume = appendAligned(self.Volume, Aligned(item.Volume, i, len(item.Y)))
+		gdr := Aligned(item.Gdr, i, len(item.Y))
+		if gdr == nil && len(self.Gdr) > 0 {
+			last := self.Gdr[len(self.Gdr)-1]
+			gdr = &last
+		}
+		self.Gdr = appendAligned(self.Gdr, gdr)
+	}
+
+	return
+}
+func appendAligned(values []float64, value *float64) []float64 {
+	if value == nil {
+		return nil
+	}
+
+	return append(values, *value)
+}
+
+func (self GraphData) Window(from, to time.Time) (window GraphData) {
+	window.add(self, from, to)
+
+	formatType := "months"
+	if span := to.Sub(from); span <= 2*24*time.Hour {
+		formatType = "hours"
+	} else if span <= 18*30*24*time.Hour {
+		formatType = "days"
+	}
+	window.Name = fmt.Sprintf("%s - %s", Display(from).Format(locale.Current().Date), Display(to).Format(locale.Current().Date))
+	window.Finalize(self.Waterline, formatType)
+	window.Labels.Waterline = locale.Tr("label.current", locale.Float(self.Waterline, 2))
+
+	return window
+}
@@ -0,0 +1,203 @@
+package market_test
+
+import (
+	"github.com/DKazakov/gdr-go/internal/fixture"
+	"github.com/DKazakov/gdr-go/market"
+	"github.com/DKazakov/gdr-go/valuation"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSetValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		prices []float64
+		want   []float64
+	}{
+		{"plain", []float64{10, 12, 11}, []float64{10, 12, 11}},
+		{"spike", []float64{10, 25, 11}, []float64{10, 10, 11}},
+		{"double", []float64{10, 20, 30}, []float64{10, 20, 30}},
+		{"drop", []float64{10, 2, 3}, []float64{10, 2, 3}},
+		{"after spike", []float64{10, 21, 21}, []float64{10, 10, 10}},
+		{"first", []float64{100, 10}, []float64{100, 10}},
+	}
+
+	for _, test := range tests {
+		var data market.GraphData
+		for i, price := range test.prices {
+			data.SetValues(time.Unix(int64(i), 0), price, float64(i*100))
+		}
+		for i := range test.want {
+			if data.X[i] != test.want[i] || data.Y[i].Unix() != int64(i) || data.Volume[i] != float64(i*100) {
+				t.Errorf("%s: got %v, want %v", test.name, data.X, test.want)
+				break
+			}
+		}
+	}
+}
+
+func TestGetGdr(t *testing.T) {
+	defer func(window int, price string) {
+		market.Model.Window, market.Model.Price = window, price
+	}(market.Model.Window, market.Model.Price)
+
+	var data market.GraphData
+	for i, bar := range [][2]float64{{20, 100}, {21, 100}, {22, 200}, {24, 100}, {25, 300}} {
+		data.SetValues(time.Unix(int64(i), 0), bar[0], bar[1])
+	}
+
+	tests := []struct {
+		window int
+		price  string
+		next   []float64
+		want   float64
+	}{
+		{3, "vwap", nil, valuation.GdrAt((22*200 + 24*100 + 25*300) / 600.0)},
+		{3, "average", nil, valuation.GdrAt((22 + 24 + 25) / 3.0)},
+		{3, "close", nil, valuation.GdrAt(25)},
+		{3, "vwap", []float64{30, 400}, valuation.GdrAt((24*100 + 25*300 + 30*400) / 800.0)},
+		{2, "vwap", nil, valuation.GdrAt((24*100 + 25*300) / 400.0)},
+		{5, "vwap", nil, 0},
+		{9, "vwap", nil, 0},
+	}
+
+	for _, test := range tests {
+		market.Model.Window, market.Model.Price = test.window, test.price
+		if got := data.GetGdr(test.next...); math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("window %d, %s, next %v: got %v, want %v", test.window, test.price, test.next, got, test.want)
+		}
+	}
+}
+
+func TestModelCheck(t *testing.T) {
+	defer func(model market.GdrModel) {
+		*market.Model = model
+	}(*market.Model)
+
+	tests := []struct {
+		model market.GdrModel
+		want  market.GdrModel
+		wrong bool
+	}{
+		{market.GdrModel{5, "close", "intraday"}, market.GdrModel{5, "close", "intraday"}, false},
+		{market.GdrModel{3, "median", "average"}, market.GdrModel{3, "vwap", "average"}, true},
+		{market.GdrModel{3, "vwap", "evening"}, market.GdrModel{3, "vwap", "auto"}, true},
+		{market.GdrModel{0, "close", "auto"}, market.GdrModel{3, "close", "auto"}, true},
+		{market.GdrModel{-2, "average", "auto"}, market.GdrModel{3, "average", "auto"}, true},
+	}
+
+	for _, test := range tests {
+		*market.Model = test.model
+		if err := market.Model.Check(); (err != nil) != test.wrong {
+			t.Errorf("%+v: error %v", test.model, err)
+		}
+		if *market.Model != test.want {
+			t.Errorf("%+v: model is %+v after the check, want %+v", test.model, *market.Model, test.want)
+		}
+	}
+}
+
+func TestFinalize(t *testing.T) {
+	data := fixture.Data(t)
+
+	today, month := data.Graph[0], data.Graph[1]
+	if data.LastPrice != today.X[len(today.X)-1] {
+		t.Errorf("last price %v, want the last intraday price %v", data.LastPrice, today.X[len(today.X)-1])
+	}
+	if data.LastClose != month.X[len(month.X)-1] {
+		t.Errorf("last close %v, want the last daily price %v", data.LastClose, month.X[len(month.X)-1])
+	}
+	if data.Gdr != month.Gdr[len(month.Gdr)-1] {
+		t.Errorf("gdr %v, want the last daily GDR %v", data.Gdr, month.Gdr[len(month.Gdr)-1])
+	}
+	if data.GdrForecast <= 0 || len(data.GdrNext.Bars) != market.Model.Window {
+		t.Errorf("forecast %v over %d bars", data.GdrForecast, len(data.GdrNext.Bars))
+	}
+
+	for i, page := range data.Graph {
+		if page.Labels == nil || page.Maximum == nil || page.Minimum == nil {
+			t.Fatalf("page %d is not finalized", i)
+		}
+		if len(page.XV) != len(page.Volume) || len(page.XGdr) != len(page.Gdr) {
+			t.Errorf("page %d: %d/%d scaled volumes, %d/%d scaled GDR", i, len(page.XV), len(page.Volume), len(page.XGdr), len(page.Gdr))
+		}
+		for _, x := range append(page.XV, page.XGdr...) {
+			if x < page.Minimum.X-1e-9 || x > page.Maximum.X+1e-9 {
+				t.Errorf("page %d: scaled value %v is out of price range %v-%v", i, x, page.Minimum.X, page.Maximum.X)
+				break
+			}
+		}
+	}
+	if today.Waterline != data.LastClose || month.Waterline != data.LastPrice {
+		t.Errorf("waterlines %v and %v, want last close and last price", today.Waterline, month.Waterline)
+	}
+
+	history := data.History
+	for i := 1; i < len(history.Y); i++ {
+		if !history.Y[i].After(history.Y[i-1]) {
+			t.Fatalf("history time goes back at %d", i)
+		}
+	}
+	if !history.Y[0].Equal(data.Graph[3].Y[0]) || !history.Y[len(history.Y)-1].Equal(today.Y[len(today.Y)-1]) {
+		t.Error("history does not span from five years ago to today")
+	}
+}
+
+func TestZone(t *testing.T) {
+	if market.Exchange.String() != "Europe/London" {
+		t.Skip("no zone database")
+	}
+	defer market.SetZone("exchange")
+
+	// 7:00 UTC is 8:00 in London in summer and 10:00 in Moscow
+	at := market.Stamp(time.Date(2017, 7, 3, 7, 0, 0, 0, time.UTC))
+	moscow := time.FixedZone("MSK", 3*60*60)
+	defer func(local *time.Location) {
+		time.Local = local
+	}(time.Local)
+	time.Local = moscow
+
+	tests := []struct {
+		zone string
+		want string
+	}{
+		{"exchange", "08:00"},
+		{"utc", "07:00"},
+		{"local", "10:00"},
+	}
+
+	for _, test := range tests {
+		if !market.SetZone(test.zone) {
+			t.Fatalf("zone %s is unknown", test.zone)
+		}
+		if got := market.HoursValueFormatter(at); got != test.want {
+			t.Errorf("%s: got %s, want %s", test.zone, got, test.want)
+		}
+	}
+	if market.SetZone("mars") {
+		t.Error("unknown zone is accepted")
+	}
+}
+
+// TestWindow zooms into the last two weeks, the window keeps the volume and
+// GDR overlays of the pages it is cut from.
+func TestWindow(t *testing.T) {
+	data := fixture.Data(t)
+	history := data.History
+	to := history.Y[len(history.Y)-1]
+	window := history.Window(to.AddDate(0, 0, -14), to)
+
+	if len(window.Y) < 2 {
+		t.Fatalf("window has %d points", len(window.Y))
+	}
+	if len(window.Volume) != len(window.Y) || len(window.XV) != len(window.Y) {
+		t.Errorf("%d volumes and %d scaled volumes for %d points", len(window.Volume), len(window.XV), len(window.Y))
+	}
+	if len(window.Gdr) != len(window.Y) || len(window.XGdr) != len(window.Y) {
+		t.Errorf("%d GDR values and %d scaled for %d points", len(window.Gdr), len(window.XGdr), len(window.Y))
+	}
+	if last := window.Gdr[len(window.Gdr)-1]; last != data.Gdr {
+		t.Errorf("GDR at the end of the window is %v, want the last daily one %v", last, data.Gdr)
+	}
+}
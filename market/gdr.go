@@ -0,0 +1,159 @@
+package market
+
+import (
+	"fmt"
+	"github.com/DKazakov/gdr-go/valuation"
+	"strings"
+	"time"
+)
+
+// sessionCloseHour is when the session is nearly over, in the exchange zone.
+const sessionCloseHour = 15
+
+// GdrModel is how the GDR price is taken: over Window daily bars, as
+// vwap, average or close, with today's volume in forecast taken as auto,
+// intraday or average.
+type GdrModel struct {
+	Window  int
+	Price   string
+	Session string
+}
+type GdrBar struct {
+	Time          time.Time
+	Price, Volume float64
+	Forecast      bool
+}
+type GdrBreakdown struct {
+	Bars    []GdrBar
+	Price   float64
+	Gdr     float64
+	Session string
+}
+
+var (
+	Model = &GdrModel{
+		Window:  3,
+		Price:   "vwap",
+		Session: "auto",
+	}
+	GdrPrices   = []string{"vwap", "average", "close"}
+	GdrSessions = []string{"auto", "intraday", "average"}
+)
+
+// Check resets a Window below one bar to 3 and an unknown Price or Session
+// to vwap or auto and tells which were wrong, so the model is checked once
+// at startup and not on every use.
+func (self *GdrModel) Check() error {
+	var unknown []string
+	if self.Window < 1 {
+		unknown = append(unknown, fmt.Sprintf("window %d", self.Window))
+		self.Window = 3
+	}
+	if !known(GdrPrices, self.Price) {
+		unknown = append(unknown, fmt.Sprintf("price %q", self.Price))
+		self.Price = GdrPrices[0]
+	}
+	if !known(GdrSessions, self.Session) {
+		unknown = append(unknown, fmt.Sprintf("session %q", self.Session))
+		self.Session = GdrSessions[0]
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("wrong gdr %s, using window %d, %s and %s", strings.Join(unknown, " and "), self.Window, self.Price, self.Session)
+	}
+
+	return nil
+}
+func known(names []string, name string) bool {
+	for _, item := range names {
+		if item == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GdrBreakdown takes the GDR over the last bars of the series, or over the
+// bars before and next price and volume if they are given.
+func (self GraphData) GdrBreakdown(next ...float64) (breakdown GdrBreakdown) {
+	var (
+		index = len(self.Volume)
+		i     = index - Model.Window
+	)
+	if i <= 0 {
+		return breakdown
+	}
+	if len(next) > 1 {
+		i = i + 1
+	}
+	for ; i < index; i++ {
+		breakdown.Bars = append(breakdown.Bars, GdrBar{self.Y[i], self.X[i], self.Volume[i], false})
+	}
+	if len(next) > 1 {
+		breakdown.Bars = append(breakdown.Bars, GdrBar{time.Now().In(Exchange), next[0], next[1], true})
+	}
+
+	breakdown.Price = gdrPrice(breakdown.Bars)
+	if breakdown.Price > 0 {
+		breakdown.Gdr = valuation.GdrAt(breakdown.Price)
+	}
+
+	return breakdown
+}
+
+func gdrPrice(bars []GdrBar) float64 {
+	var (
+		count float64
+		summ  float64
+	)
+	if len(bars) == 0 {
+		return 0
+	}
+
+	switch Model.Price {
+	case "close":
+		return bars[len(bars)-1].Price
+	case "average":
+		for _, bar := range bars {
+			count = count + bar.Price
+		}
+		return count / float64(len(bars))
+	}
+
+	for _, bar := range bars {
+		count = count + bar.Price*bar.Volume
+		summ = summ + bar.Volume
+	}
+	if summ == 0 {
+		return 0
+	}
+
+	return count / summ
+}
+
+// ForecastVolume returns the volume today's bar is expected to close with:
+// intraday volume once the session is nearly over, the monthly average before.
+func (self *Data) ForecastVolume() (volume float64, session string) {
+	session = Model.Session
+	if session == "auto" {
+		session = "average"
+		if len(self.Graph[0].X) > 0 && self.LastUpdate.In(Exchange).Hour() > sessionCloseHour {
+			session = "intraday"
+		}
+	}
+
+	if session == "intraday" {
+		for _, val := range self.Graph[0].Volume {
+			volume = volume + val
+		}
+	} else {
+		for _, val := range self.Graph[1].Volume {
+			volume = volume + val
+		}
+		if len(self.Graph[1].Volume) > 0 {
+			volume = volume / float64(len(self.Graph[1].Volume))
+		}
+	}
+
+	return volume, session
+}
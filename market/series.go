@@ -0,0 +1,110 @@
+package market
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+var SeriesNames = []string{"today", "month", "year", "fiveyears"}
+
+type SeriesPoint struct {
+	Time         time.Time `json:"time"`
+	Price        float64   `json:"price"`
+	Volume       *float64  `json:"volume,omitempty"`
+	Gdr          *float64  `json:"gdr,omitempty"`
+	ScaledVolume *float64  `json:"scaled_volume,omitempty"`
+	ScaledGdr    *float64  `json:"scaled_gdr,omitempty"`
+}
+
+func (self GraphData) Points() (points []SeriesPoint) {
+	for i, y := range self.Y {
+		point := SeriesPoint{Time: y, Price: self.X[i]}
+		point.Volume = Aligned(self.Volume, i, len(self.Y))
+		point.ScaledVolume = Aligned(self.XV, i, len(self.Y))
+		point.Gdr = Aligned(self.Gdr, i, len(self.Y))
+		point.ScaledGdr = Aligned(self.XGdr, i, len(self.Y))
+		points = append(points, point)
+	}
+
+	return points
+}
+
+// Aligned returns the value for point i of length. GDR values are collected
+// only once there is enough history, so shorter series are aligned to the
+// end of the price series.
+func Aligned(values []float64, i, length int) *float64 {
+	i = i - (length - len(values))
+	if i < 0 || i >= len(values) {
+		return nil
+	}
+
+	return &values[i]
+}
+
+func WriteSeries(out io.Writer, points []SeriesPoint, format string) (err error) {
+	switch format {
+	case "csv":
+		writer := csv.NewWriter(out)
+		writer.Write([]string{"time", "price", "volume", "gdr", "scaled_volume", "scaled_gdr"})
+		for _, point := range points {
+			writer.Write([]string{
+				point.Time.Format(time.RFC3339),
+				strconv.FormatFloat(point.Price, 'f', -1, 64),
+				optional(point.Volume),
+				optional(point.Gdr),
+				optional(point.ScaledVolume),
+				optional(point.ScaledGdr),
+			})
+		}
+		writer.Flush()
+		err = writer.Error()
+	case "jsonl":
+		encoder := json.NewEncoder(out)
+		for _, point := range points {
+			if err = encoder.Encode(point); err != nil {
+				break
+			}
+		}
+	default:
+		err = fmt.Errorf("unknown format %q, use csv or jsonl", format)
+	}
+
+	return err
+}
+func optional(value *float64) string {
+	if value == nil {
+		return ""
+	}
+
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
+
+// ExportSeries writes every page to dir as a csv or jsonl file.
+func (self *Data) ExportSeries(dir, format string) (paths []string, err error) {
+	stamp := time.Now().Format("20060102-150405")
+
+	for i, item := range self.Graph {
+		path := filepath.Join(dir, fmt.Sprintf("gdr-%s-%s.%s", SeriesNames[i], stamp, format))
+
+		f, err := os.Create(path)
+		if err != nil {
+			return paths, err
+		}
+		err = WriteSeries(f, item.Points(), format)
+		f.Close()
+		if err != nil {
+			os.Remove(path)
+			return paths, err
+		}
+
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
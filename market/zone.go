@@ -0,0 +1,79 @@
+package market
+
+import (
+	"strings"
+	"time"
+)
+
+// Exchange is the zone of the London Stock Exchange: times of the series
+// are kept in it and the trading session is counted in its hours. Without
+// the zone database it falls back to GMT, an hour off in summer, and
+// ExchangeError tells why.
+var Exchange, exchangeError = loadZone("Europe/London", "GMT")
+
+// ZoneNames are the zones times can be shown in.
+var ZoneNames = []string{"exchange", "local", "utc"}
+
+var zoneName = "exchange"
+
+func loadZone(name, fallback string) (*time.Location, error) {
+	zone, err := time.LoadLocation(name)
+	if err != nil {
+		return time.FixedZone(fallback, 0), err
+	}
+
+	return zone, nil
+}
+
+// ExchangeError is why the exchange zone fell back to GMT, nil if it did not.
+func ExchangeError() error {
+	return exchangeError
+}
+
+// SetZone picks the zone times are shown in, false if name is unknown.
+func SetZone(name string) bool {
+	name = strings.ToLower(name)
+	for _, item := range ZoneNames {
+		if item == name {
+			zoneName = name
+			return true
+		}
+	}
+
+	return false
+}
+
+// Zone is where times are shown: the exchange zone, the local one or UTC.
+func Zone() *time.Location {
+	switch zoneName {
+	case "local":
+		return time.Local
+	case "utc":
+		return time.UTC
+	}
+
+	return Exchange
+}
+
+// Display returns t in the display zone.
+func Display(t time.Time) time.Time {
+	return t.In(Zone())
+}
+
+// Stamp is the chart coordinate of t, charts take times as float nanoseconds.
+func Stamp(t time.Time) float64 {
+	return float64(t.UnixNano())
+}
+
+// Stamps are the chart coordinates of times.
+func Stamps(times []time.Time) []float64 {
+	stamps := make([]float64, len(times))
+	for i, t := range times {
+		stamps[i] = Stamp(t)
+	}
+
+	return stamps
+}
+func unstamp(v float64) time.Time {
+	return time.Unix(0, int64(v))
+}
@@ -0,0 +1,307 @@
+// Package provider fetches prices from the London Stock Exchange chart
+// service and the exchange rate from fixer.io.
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DKazakov/gdr-go/logging"
+	"github.com/DKazakov/gdr-go/market"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UpdateTick is how often the sources are fetched again.
+const UpdateTick time.Duration = 2 * 60 * time.Second
+
+// stockOffset is what the chart service adds to its times, it is asked for
+// them as on a clock of UTC+1 with OffSet -60.
+const stockOffset time.Duration = 60 * time.Minute
+
+var (
+	StockUrl    = "http://charts.londonstockexchange.com/WebCharts/services/ChartWService.asmx/GetPricesWithVolume"
+	ExchangeUrl = "http://data.fixer.io/latest?symbols=RUB,USD&access_key=2c9d0b143d653c87830759e564b07708"
+)
+
+type JsonStock struct {
+	Data  [][]float64        `json:"d"`
+	Base  string             `json:"base"`
+	Date  string             `json:"date"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+type Source struct {
+	Name     string
+	Url      string
+	Method   string
+	Postdata string
+	Process  func(*JsonStock) []market.GraphData
+	mu       sync.Mutex
+	stats    SourceStats
+}
+
+// SourceStats describes the last request of a source. Requests update it
+// under the source lock, everyone else reads a copy from Snapshot.
+type SourceStats struct {
+	Checked   time.Time
+	Updated   time.Time
+	LastError string
+	Duration  time.Duration
+	Errors    int
+	Code      int
+	Size      int
+}
+
+// Fetch gets the pages of one source, Get or a wrapper around it.
+type Fetch func(context.Context, *Source) ([]market.GraphData, error)
+
+func InitSource(options ...string) (self *Source) {
+	self = new(Source)
+
+	if options[0] == "GET" {
+		self.Method = options[0]
+		self.Url = options[1]
+	} else {
+		self.Method = "POST"
+		self.Postdata = options[0]
+		self.Url = options[1]
+	}
+
+	return self
+}
+
+func (self *Source) Get(ctx context.Context) (data []market.GraphData, err error) {
+	var (
+		req   *http.Request
+		resp  *http.Response
+		start = time.Now()
+		stats = self.Snapshot()
+	)
+
+	if self.Postdata == "" {
+		req, err = http.NewRequest("GET", self.Url, nil)
+	} else {
+		req, err = http.NewRequest("POST", self.Url, strings.NewReader(self.Postdata))
+		if req != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+	}
+	if err == nil {
+		resp, err = http.DefaultClient.Do(req.WithContext(ctx))
+	}
+	stats.Code = 0
+	stats.Size = 0
+	if err != nil {
+		err = fmt.Errorf("http request error: %s", err)
+	} else {
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		stats.Code = resp.StatusCode
+		stats.Size = len(body)
+		if resp.StatusCode == 200 {
+			jsonInterface := new(JsonStock)
+			err = json.Unmarshal(body, jsonInterface)
+			if err != nil {
+				err = fmt.Errorf("JSON error: %s", err)
+			} else {
+				if self.Process != nil {
+					data = self.Process(jsonInterface)
+				}
+			}
+		} else {
+			err = errors.New(fmt.Sprintf("non-200 response, data: %s", self.Postdata))
+			logging.Debug("failed response", "source", self.Name, "url", self.Url, "response", string(body))
+		}
+	}
+
+	stats.Checked = time.Now()
+	stats.Duration = stats.Checked.Sub(start)
+	if err != nil && ctx.Err() != nil {
+		logging.Debug("request cancelled", "source", self.Name, "url", self.Url, "duration", stats.Duration)
+		stats.LastError = err.Error()
+	} else if err != nil {
+		logging.Error("request failed", "source", self.Name, "url", self.Url, "status", stats.Code, "duration", stats.Duration, "err", err)
+		stats.LastError = err.Error()
+		stats.Errors++
+	} else {
+		logging.Debug("request done", "source", self.Name, "url", self.Url, "status", stats.Code, "duration", stats.Duration, "size", stats.Size)
+		stats.Updated = stats.Checked
+		stats.LastError = ""
+	}
+
+	self.mu.Lock()
+	self.stats = stats
+	self.mu.Unlock()
+
+	return
+}
+func (self *Source) Snapshot() SourceStats {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	return self.stats
+}
+
+// Status is waiting before the first request, then done or error.
+func (self SourceStats) Status() string {
+	if self.Checked.IsZero() {
+		return "waiting"
+	} else if self.LastError != "" {
+		return "error"
+	}
+
+	return "done"
+}
+
+func wrapper(data ...market.GraphData) (ret []market.GraphData) {
+	return data
+}
+
+// stockTime converts milliseconds the chart service counts in, shifted by
+// stockOffset, to time in the exchange zone.
+func stockTime(ms float64) time.Time {
+	return time.Unix(0, int64(ms)*int64(time.Millisecond)).Add(-stockOffset).In(market.Exchange)
+}
+
+func DaysCallback(jsonInterface *JsonStock) []market.GraphData {
+	var (
+		month     = new(market.GraphData)
+		year      = new(market.GraphData)
+		lastMonth = len(jsonInterface.Data) - 31
+	)
+
+	for i, e := range jsonInterface.Data {
+		date := stockTime(e[0])
+		year.SetValues(date, e[1], e[6])
+		year.SetOhlc(e)
+		if i > lastMonth {
+			month.SetValues(date, e[1], e[6])
+			month.SetOhlc(e)
+			gdr := year.GetGdr()
+			month.SetGdr(gdr)
+		}
+	}
+
+	return wrapper(*month, *year)
+}
+func WeeksCallback(jsonInterface *JsonStock) []market.GraphData {
+	var (
+		fiveyears = new(market.GraphData)
+	)
+
+	for _, e := range jsonInterface.Data {
+		fiveyears.SetValues(stockTime(e[0]), e[1])
+		fiveyears.SetOhlc(e)
+	}
+
+	return wrapper(*fiveyears)
+}
+func HoursCallback(jsonInterface *JsonStock) []market.GraphData {
+	var (
+		today = new(market.GraphData)
+	)
+
+	for _, e := range jsonInterface.Data {
+		today.SetValues(stockTime(e[0]), e[1], e[6])
+		today.SetOhlc(e)
+	}
+
+	return wrapper(*today)
+}
+func ExchangeCallback(jsonInterface *JsonStock) []market.GraphData {
+	dollar := new(market.GraphData)
+	dollar.SetValues(time.Time{}, float64(jsonInterface.Rates["RUB"])/float64(jsonInterface.Rates["USD"]))
+
+	return wrapper(*dollar)
+}
+
+func GetSources() map[string]*Source {
+	days := InitSource(makeStockData("1d", "1y"))
+	days.Process = DaysCallback
+
+	weeks := InitSource(makeStockData("1d", "5y"))
+	weeks.Process = WeeksCallback
+
+	hours := InitSource(makeStockData("1mm", "1d"))
+	hours.Process = HoursCallback
+
+	exchange := InitSource("GET", ExchangeUrl)
+	exchange.Process = ExchangeCallback
+
+	source := map[string]*Source{
+		"days":     days,
+		"weeks":    weeks,
+		"hours":    hours,
+		"exchange": exchange,
+	}
+	for name, item := range source {
+		item.Name = name
+	}
+
+	return source
+}
+
+// makeStockData asks for times shifted by stockOffset, OffSet is in minutes
+// with the sign of JavaScript's getTimezoneOffset.
+func makeStockData(st, tf string) (data, url string) {
+	return fmt.Sprintf(`{"request":{"SampleTime":"%s","TimeFrame":"%s","RequestedDataSetType":"ohlc","ChartPriceType":"price","Key":"MAIL.LID","OffSet":%d,"FromDate":null,"ToDate":null,"UseDelay":true,"KeyType":"Topic","KeyType2":"Topic","Language":"en"}}`, st, tf, -int(stockOffset/time.Minute)), StockUrl
+}
+
+func Get(ctx context.Context, item *Source) ([]market.GraphData, error) {
+	return item.Get(ctx)
+}
+
+// Update fetches all sources with fetch and returns finalized data. Sources
+// are fetched in parallel, but only this goroutine writes to the new data,
+// and the ones that failed are left empty and named in the error. The data
+// goes to publish unless ctx was cancelled on the way, then it is only
+// returned.
+func Update(ctx context.Context, sources map[string]*Source, fetch Fetch, publish func(*market.Data)) (*market.Data, error) {
+	type result struct {
+		name  string
+		pages []market.GraphData
+		err   error
+	}
+	var (
+		wg      sync.WaitGroup
+		data    = new(market.Data).Init()
+		results = make(chan result, len(sources))
+		failed  []string
+	)
+
+	for name, item := range sources {
+		wg.Add(1)
+		go func(name string, item *Source) {
+			defer wg.Done()
+			pages, err := fetch(ctx, item)
+			results <- result{name, pages, err}
+		}(name, item)
+	}
+	wg.Wait()
+	close(results)
+
+	for item := range results {
+		if item.err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %s", item.name, item.err))
+			continue
+		}
+		data.Set(item.name, item.pages)
+	}
+	data.Finalize()
+	if ctx.Err() == nil && publish != nil {
+		publish(data)
+	}
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return data, errors.New(strings.Join(failed, "; "))
+	}
+
+	return data, nil
+}
@@ -0,0 +1,100 @@
+package provider_test
+
+import (
+	"context"
+	"github.com/DKazakov/gdr-go/internal/fixture"
+	"github.com/DKazakov/gdr-go/market"
+	"github.com/DKazakov/gdr-go/provider"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	fixture.Setup()
+
+	os.Exit(m.Run())
+}
+
+func TestDaysCallback(t *testing.T) {
+	stock := fixture.Stock(t, "lse_days.json")
+	pages := provider.DaysCallback(stock)
+	if len(pages) != 2 {
+		t.Fatalf("got %d pages, want month and year", len(pages))
+	}
+
+	month, year := pages[0], pages[1]
+	if len(year.X) != len(stock.Data) || len(year.Ohlc) != len(stock.Data) || len(year.Volume) != len(stock.Data) {
+		t.Errorf("year has %d prices, %d bars, %d volumes, want %d of each", len(year.X), len(year.Ohlc), len(year.Volume), len(stock.Data))
+	}
+	if len(month.X) != 30 {
+		t.Errorf("month has %d prices, want 30", len(month.X))
+	}
+
+	// the service sends times an hour ahead, as asked with OffSet -60
+	last := stock.Data[len(stock.Data)-1]
+	if month.Y[len(month.Y)-1].Add(time.Hour).UnixNano() != int64(last[0])*int64(time.Millisecond) || month.X[len(month.X)-1] != last[1] {
+		t.Errorf("last month point is %v at %v, want %v at %v ms", month.X[len(month.X)-1], month.Y[len(month.Y)-1], last[1], last[0])
+	}
+	if len(month.Gdr) != len(month.X) {
+		t.Errorf("month has %d GDR values for %d prices", len(month.Gdr), len(month.X))
+	}
+	if bar := month.Ohlc[len(month.Ohlc)-1]; bar != [4]float64{last[1], last[2], last[3], last[4]} {
+		t.Errorf("last bar is %v, want %v", bar, last[1:5])
+	}
+}
+
+func TestHoursCallback(t *testing.T) {
+	stock := fixture.Stock(t, "lse_hours.json")
+	pages := provider.HoursCallback(stock)
+	if len(pages) != 1 {
+		t.Fatalf("got %d pages, want today", len(pages))
+	}
+
+	today := pages[0]
+	if len(today.Y) != len(stock.Data) || len(today.Volume) != len(stock.Data) {
+		t.Fatalf("today has %d points and %d volumes, want %d", len(today.Y), len(today.Volume), len(stock.Data))
+	}
+	for i, e := range stock.Data {
+		if today.Y[i].Add(time.Hour).UnixNano() != int64(e[0])*int64(time.Millisecond) || today.X[i] != e[1] || today.Volume[i] != e[6] {
+			t.Fatalf("point %d is %v %v %v, want %v ms %v %v", i, today.Y[i], today.X[i], today.Volume[i], e[0], e[1], e[6])
+		}
+	}
+	// the session opens at 8:00 London time, GMT in November
+	if first := today.Y[0]; first.Location() != market.Exchange || first.Hour() != 8 || first.Minute() != 0 {
+		t.Errorf("session opens at %v, want 08:00 in the exchange zone", first)
+	}
+}
+
+func TestExchangeCallback(t *testing.T) {
+	pages := provider.ExchangeCallback(fixture.Stock(t, "fx.json"))
+	if want := 69.5 / 1.18; pages[0].X[0] != want {
+		t.Errorf("rate is %v, want %v", pages[0].X[0], want)
+	}
+}
+
+func TestGetErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	source := provider.InitSource("GET", server.URL)
+	source.Process = provider.HoursCallback
+	for i := 1; i <= 2; i++ {
+		if _, err := source.Get(context.Background()); err == nil {
+			t.Fatal("no error for a failed response")
+		}
+		if stats := source.Snapshot(); stats.Code != http.StatusServiceUnavailable || stats.Errors != i || stats.LastError == "" || !stats.Updated.IsZero() {
+			t.Errorf("stats after %d failures: %+v", i, stats)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := source.Get(ctx); err == nil {
+		t.Error("no error for a cancelled request")
+	}
+}
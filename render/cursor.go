@@ -0,0 +1,115 @@
+package render
+
+import (
+	"fmt"
+	"github.com/DKazakov/gdr-go/locale"
+	"github.com/DKazakov/gdr-go/market"
+	"github.com/DKazakov/gdr-go/valuation"
+	"github.com/wcharczuk/go-chart"
+	"time"
+)
+
+func (self *Graph) MoveCursor(step int) {
+	length := len(self.Current().Y)
+	if length == 0 {
+		return
+	}
+
+	if !self.cursorOn {
+		self.cursor = length - 1
+		self.cursorOn = true
+	} else {
+		self.cursor = self.cursor + step
+	}
+
+	if self.cursor < 0 {
+		self.cursor = 0
+	} else if self.cursor > length-1 {
+		self.cursor = length - 1
+	}
+
+	return
+}
+func (self Graph) TimeAt(share float64) time.Time {
+	source := self.Current()
+	if len(source.Y) == 0 {
+		return time.Time{}
+	}
+
+	first, last := source.Y[0], source.Y[len(source.Y)-1]
+	return first.Add(time.Duration(float64(last.Sub(first)) * share))
+}
+func (self *Graph) CursorAt(share float64) {
+	source := self.Current()
+	if len(source.Y) == 0 {
+		return
+	}
+
+	target := self.TimeAt(share)
+	self.cursor = 0
+	for i, y := range source.Y {
+		if !y.After(target) {
+			self.cursor = i
+		}
+	}
+	if self.cursor < len(source.Y)-1 && source.Y[self.cursor+1].Sub(target) < target.Sub(source.Y[self.cursor]) {
+		self.cursor++
+	}
+	self.cursorOn = true
+
+	return
+}
+func (self *Graph) HideCursor() {
+	self.cursorOn = false
+
+	return
+}
+
+func (self Graph) cursorSeries(source market.GraphData) (chart.Series, bool) {
+	if !self.cursorOn || self.cursor >= len(source.Y) || source.Maximum == nil {
+		return nil, false
+	}
+
+	y := source.Y[self.cursor]
+	return chart.ContinuousSeries{
+		Name: market.Display(y).Format(locale.Current().Date + " " + locale.Current().Hours),
+		Style: chart.Style{
+			Show:        true,
+			StrokeColor: CurrentTheme().Cursor,
+			StrokeWidth: 1.0,
+		},
+		XValues: []float64{market.Stamp(y), market.Stamp(y)},
+		YValues: []float64{source.Minimum.Chart, source.Maximum.Chart},
+	}, true
+}
+
+func (self Graph) Tooltip() string {
+	source := self.Current()
+	if !self.cursorOn || self.cursor >= len(source.Y) {
+		return ""
+	}
+
+	var (
+		i     = self.cursor
+		price = source.X[i]
+		gdr   = valuation.GdrAt(price)
+		value = valuation.OptionsValue * (price - valuation.OptionsVesting)
+		text  = market.Display(source.Y[i]).Format(locale.Current().Date + " " + locale.Current().Hours)
+	)
+
+	if len(source.Ohlc) == len(source.Y) {
+		bar := source.Ohlc[i]
+		text += fmt.Sprintf("  O %.2f H %.2f L %.2f C %.2f", bar[0], bar[1], bar[2], bar[3])
+	} else {
+		text += fmt.Sprintf("  %.2f", price)
+	}
+	if volume := market.Aligned(source.Volume, i, len(source.Y)); volume != nil {
+		text += fmt.Sprintf("  V %.3fkk", *volume/1000000)
+	}
+	if computed := market.Aligned(source.Gdr, i, len(source.Y)); computed != nil {
+		gdr = *computed
+	}
+	text += "  " + locale.Tr("cursor.value", locale.Float(gdr, 2), locale.Number(value), locale.Number(value*self.dollar))
+
+	return text
+}
@@ -0,0 +1,60 @@
+package render
+
+import (
+	"fmt"
+	"github.com/wcharczuk/go-chart"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ExportOptions are where and how charts are saved: png or svg format,
+// size in pixels and resolution.
+type ExportOptions struct {
+	Dir           string
+	Format        string
+	Width, Height int
+	DPI           float64
+}
+
+func (self Graph) Export(options *ExportOptions) (path string, err error) {
+	var provider chart.RendererProvider
+
+	switch options.Format {
+	case "png":
+		provider = chart.PNG
+	case "svg":
+		provider = chart.SVG
+	default:
+		return "", fmt.Errorf("unknown export format %q, use png or svg", options.Format)
+	}
+
+	name := fmt.Sprintf("gdr-%d-%s.%s", self.page+1, time.Now().Format("20060102-150405"), options.Format)
+	path = filepath.Join(options.Dir, name)
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	graph := self.Chart(options.Width, options.Height)
+	graph.DPI = options.DPI
+	if err = graph.Render(provider, f); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+func (self Graph) ExportAll(options *ExportOptions) (paths []string, err error) {
+	for page := 0; page < self.PageCount(); page++ {
+		self.SetPage(page)
+		path, err := self.Export(options)
+		if err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
@@ -0,0 +1,304 @@
+// Package render draws the pages of market data and the value projection
+// as charts, keeping the page, zoom window and cursor of a view.
+package render
+
+import (
+	"bytes"
+	"github.com/DKazakov/gdr-go/locale"
+	"github.com/DKazakov/gdr-go/market"
+	"github.com/DKazakov/gdr-go/valuation"
+	"github.com/wcharczuk/go-chart"
+	"time"
+)
+
+const minWindowPoints = 3
+
+type Graph struct {
+	pages    [4]market.GraphData
+	page     int
+	history  market.GraphData
+	from, to time.Time
+	zoomed   bool
+	cursor   int
+	cursorOn bool
+	dollar   float64
+	fan      *valuation.Projection
+}
+
+// Init takes new data keeping the page, zoom and cursor of the view. The
+// projection page may be gone with the new data, then the last page is shown.
+func (self *Graph) Init(data *market.Data) *Graph {
+	self.pages = data.Graph
+	self.history = data.History
+	self.dollar = data.Dollar
+	self.fan = data.Projection
+	if maxpage := self.PageCount() - 1; self.page > maxpage {
+		self.page = maxpage
+	}
+
+	return self
+}
+
+func (self Graph) Current() market.GraphData {
+	if self.zoomed {
+		return self.history.Window(self.from, self.to)
+	}
+	if self.page >= len(self.pages) {
+		return market.GraphData{}
+	}
+
+	return self.pages[self.page]
+}
+func (self Graph) PageCount() int {
+	if self.fan != nil {
+		return len(self.pages) + 1
+	}
+
+	return len(self.pages)
+}
+func (self *Graph) SetRange(from, to time.Time) bool {
+	if len(self.history.Y) == 0 || !to.After(from) {
+		return false
+	}
+
+	first, last := self.history.Y[0], self.history.Y[len(self.history.Y)-1]
+	if span := to.Sub(from); span > last.Sub(first) {
+		from, to = first, last
+	} else if from.Before(first) {
+		from, to = first, first.Add(span)
+	} else if to.After(last) {
+		from, to = last.Add(-span), last
+	}
+
+	points := 0
+	for _, y := range self.history.Y {
+		if !y.Before(from) && !y.After(to) {
+			points++
+		}
+	}
+	if points < minWindowPoints {
+		return false
+	}
+
+	self.from, self.to = from, to
+	self.zoomed = true
+	self.cursorOn = false
+
+	return true
+}
+func (self *Graph) bounds() (from, to time.Time) {
+	if self.zoomed {
+		return self.from, self.to
+	}
+
+	source := self.Current()
+	if len(source.Y) == 0 {
+		return from, to
+	}
+
+	return source.Y[0], source.Y[len(source.Y)-1]
+}
+func (self *Graph) Zoom(factor float64) bool {
+	from, to := self.bounds()
+	center := from.Add(to.Sub(from) / 2)
+	span := time.Duration(float64(to.Sub(from)) * factor / 2)
+
+	return self.SetRange(center.Add(-span), center.Add(span))
+}
+func (self *Graph) Pan(share float64) bool {
+	from, to := self.bounds()
+	shift := time.Duration(float64(to.Sub(from)) * share)
+
+	return self.SetRange(from.Add(shift), to.Add(shift))
+}
+
+func (self Graph) NextPage() int {
+	page := self.page + 1
+	maxpage := self.PageCount() - 1
+	if page > maxpage {
+		page = 0
+	}
+
+	return page
+}
+func (self Graph) PrevPage() int {
+	page := self.page - 1
+	maxpage := self.PageCount() - 1
+	if page < 0 {
+		page = maxpage
+	}
+
+	return page
+}
+
+func (self *Graph) SetPage(page int) {
+	maxpage := self.PageCount() - 1
+
+	if page < 0 {
+		page = 0
+	} else if page > maxpage {
+		page = maxpage
+	}
+
+	self.page = page
+	self.zoomed = false
+	self.cursorOn = false
+
+	return
+}
+
+func (self Graph) PageAt(width, x int) (int, bool) {
+	if self.Tooltip() != "" {
+		return 0, false
+	}
+
+	offset := x - (int(width/2) - 2)
+	if offset < 0 || offset/2 >= self.PageCount() {
+		return 0, false
+	}
+
+	return offset / 2, true
+}
+func (self Graph) Paginate() string {
+	if self.zoomed {
+		return self.indicator(-1) + self.Current().Name
+	}
+	if self.page >= len(self.pages) && self.fan != nil {
+		return self.PageName(self.page) + ": " + summary(self.fan)
+	}
+
+	return self.PageName(self.page)
+}
+func (self Graph) PageName(page int) string {
+	return self.indicator(page) + locale.Tr(market.PageKeys[page])
+}
+
+// indicator draws page numbers as circled digits, the active one filled.
+func (self Graph) indicator(page int) (status string) {
+	for i := 0; i < self.PageCount(); i++ {
+		if i == page {
+			status += string(rune(0x2776+i)) + " "
+		} else {
+			status += string(rune(0x2780+i)) + " "
+		}
+	}
+
+	return status
+}
+
+func (self Graph) Render(imageWidth, imageHeight int) *bytes.Buffer {
+	buffer := bytes.NewBuffer([]byte{})
+	graph := self.Chart(imageWidth, imageHeight)
+	graph.Render(chart.PNG, buffer)
+
+	return buffer
+}
+func (self Graph) Chart(imageWidth, imageHeight int) chart.Chart {
+	if self.page >= len(self.pages) && self.fan != nil {
+		return projectionChart(self.fan, imageWidth, imageHeight)
+	}
+
+	source := self.Current()
+	if len(source.Y) == 0 || source.Labels == nil {
+		return chart.Chart{Width: imageWidth, Height: imageHeight}
+	}
+
+	times := market.Stamps(source.Y)
+	theme := CurrentTheme()
+	series := []chart.Series{}
+
+	series = append(series, chart.ContinuousSeries{
+		Name: source.Labels.X,
+		Style: chart.Style{
+			Show:        true,
+			StrokeColor: theme.Price,
+			FillColor:   theme.Price,
+		},
+		XValues: times,
+		YValues: source.X,
+	})
+	if source.Waterline > 0 && len(source.Y) > 0 {
+		series = append(series, chart.ContinuousSeries{
+			Name: source.Labels.Waterline,
+			Style: chart.Style{
+				Show:        true,
+				StrokeColor: theme.Waterline,
+				StrokeWidth: 1.0,
+			},
+			XValues: []float64{times[0], times[len(times)-1]},
+			YValues: []float64{source.Waterline, source.Waterline},
+		})
+	}
+	if len(source.XV) > 0 && len(source.Y) > 0 {
+		series = append(series, chart.ContinuousSeries{
+			Name: source.Labels.XV,
+			Style: chart.Style{
+				Show:        true,
+				StrokeColor: theme.Volume,
+				StrokeWidth: 1.5,
+			},
+			XValues: times,
+			YValues: source.XV,
+		})
+	}
+	if len(source.XGdr) > 0 && len(source.Y) > 0 {
+		series = append(series, chart.ContinuousSeries{
+			Name: source.Labels.XGdr,
+			Style: chart.Style{
+				Show:        true,
+				StrokeColor: theme.Gdr,
+				StrokeWidth: 1.5,
+			},
+			XValues: times,
+			YValues: source.XGdr,
+		})
+	}
+	if cursor, ok := self.cursorSeries(source); ok {
+		series = append(series, cursor)
+	}
+
+	graph := chart.Chart{
+		Width:  imageWidth,
+		Height: imageHeight,
+		Background: chart.Style{
+			Padding: chart.Box{
+				Top:    20,
+				Left:   0,
+				Right:  0,
+				Bottom: 0,
+			},
+		},
+		XAxis: chart.XAxis{
+			Style: chart.Style{
+				Show:     true,
+				FontSize: 7.0,
+			},
+			TickPosition:   chart.TickPositionBetweenTicks,
+			ValueFormatter: source.ValueFormatter,
+		},
+		YAxis: chart.YAxis{
+			Style: chart.Style{
+				Show:     true,
+				FontSize: 7.0,
+			},
+			Range: &chart.ContinuousRange{
+				Max: source.Maximum.Chart,
+				Min: source.Minimum.Chart,
+			},
+		},
+		YAxisSecondary: chart.YAxis{
+			Style: chart.Style{
+				Show:     true,
+				FontSize: 7.0,
+			},
+			Range: &chart.ContinuousRange{
+				Max: source.Maximum.Chart,
+				Min: source.Minimum.Chart,
+			},
+		},
+		Series: series,
+	}
+	theme.decorate(&graph)
+
+	return graph
+}
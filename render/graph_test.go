@@ -0,0 +1,251 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/DKazakov/gdr-go/internal/fixture"
+	"github.com/DKazakov/gdr-go/locale"
+	"github.com/DKazakov/gdr-go/market"
+	"github.com/wcharczuk/go-chart"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+// Charts are rendered at a fixed size and resolution with the font go-chart
+// embeds, from fixture data in the light theme and the Russian locale, so an
+// upgrade of the library is the only thing that may change them.
+const (
+	goldenWidth  = 800
+	goldenHeight = 400
+	// share of pixels allowed to differ noticeably from the golden image
+	pixelShare = 0.002
+	// colour difference on the YIQ scale of pixelmatch, 0 to 1
+	pixelThreshold = 0.1
+	// coordinates in SVG may move by this many pixels
+	svgTolerance = 0.5
+)
+
+var (
+	svgText   = regexp.MustCompile(`>[^<]*<`)
+	svgNumber = regexp.MustCompile(`-?[0-9]+(\.[0-9]+)?`)
+)
+
+func TestMain(m *testing.M) {
+	fixture.Setup()
+	locale.Set("ru")
+	SetTheme("light")
+
+	os.Exit(m.Run())
+}
+
+func fixtureGraph(t *testing.T) *Graph {
+	data := fixture.Data(t)
+
+	// the projection is random, it is not part of the golden set
+	data.Projection = nil
+
+	return new(Graph).Init(data)
+}
+
+func TestRender(t *testing.T) {
+	graph := fixtureGraph(t)
+	views := map[string]func(){}
+	for page := 0; page < graph.PageCount(); page++ {
+		page := page
+		views[fmt.Sprintf("page%d", page+1)] = func() { graph.SetPage(page) }
+	}
+	views["cursor"] = func() {
+		graph.SetPage(1)
+		graph.CursorAt(0.5)
+	}
+	views["window"] = func() {
+		graph.SetPage(2)
+		graph.Zoom(0.25)
+	}
+
+	for name, view := range views {
+		view()
+
+		picture := graph.Render(goldenWidth, goldenHeight)
+		goldenImage(t, "chart-"+name+".png", picture.Bytes())
+
+		svg := bytes.NewBuffer([]byte{})
+		source := graph.Chart(goldenWidth, goldenHeight)
+		source.DPI = chart.DefaultDPI
+		if err := source.Render(chart.SVG, svg); err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		goldenSvg(t, "chart-"+name+".svg", svg.Bytes())
+	}
+}
+
+// TestRefresh takes data without the projection and prices while the
+// projection page is shown, as after failed fetches.
+func TestRefresh(t *testing.T) {
+	graph := fixtureGraph(t)
+	graph.page = len(graph.pages)
+	graph.Init(new(market.Data).Init())
+
+	if graph.page != len(graph.pages)-1 {
+		t.Errorf("page %d after the projection is gone, want the last one %d", graph.page, len(graph.pages)-1)
+	}
+	if source := graph.Chart(goldenWidth, goldenHeight); len(source.Series) != 0 {
+		t.Errorf("chart of empty data has %d series", len(source.Series))
+	}
+	if picture := graph.Render(goldenWidth, goldenHeight); picture == nil {
+		t.Error("no picture of empty data")
+	}
+}
+
+// goldenImage compares a PNG with testdata/golden/name pixel by pixel,
+// -update rewrites the file.
+func goldenImage(t *testing.T, name string, got []byte) {
+	want, ok := goldenFile(t, name, got)
+	if !ok {
+		return
+	}
+
+	gotImage, err := png.Decode(bytes.NewReader(got))
+	if err != nil {
+		t.Fatalf("%s: %s", name, err)
+	}
+	wantImage, err := png.Decode(bytes.NewReader(want))
+	if err != nil {
+		t.Fatalf("golden %s: %s", name, err)
+	}
+
+	if share := imageDiff(gotImage, wantImage); share > pixelShare {
+		diff := filepath.Join(os.TempDir(), name)
+		ioutil.WriteFile(diff, got, 0644)
+		t.Errorf("%s: %.2f%% of pixels differ from the golden image, see %s, run go test -update if the change is expected", name, share*100, diff)
+	}
+}
+
+// goldenSvg compares an SVG with testdata/golden/name: texts exactly and
+// coordinates within svgTolerance, -update rewrites the file.
+func goldenSvg(t *testing.T, name string, got []byte) {
+	want, ok := goldenFile(t, name, got)
+	if !ok {
+		return
+	}
+
+	if diff := svgDiff(string(got), string(want)); diff != "" {
+		t.Errorf("%s: %s, run go test -update if the change is expected", name, diff)
+	}
+}
+
+// goldenFile reads the golden file or writes it with -update, a missing
+// one fails the test.
+func goldenFile(t *testing.T, name string, got []byte) ([]byte, bool) {
+	want, ok := fixture.GoldenFile(t, name, got)
+	if ok && want == nil {
+		t.Fatalf("%s is missing, run go test -run TestRender -update to create it", fixture.Path(filepath.Join("golden", name)))
+	}
+
+	return want, ok
+}
+
+// imageDiff returns the share of pixels whose colours differ by more than
+// pixelThreshold, measured in YIQ space the way pixelmatch does it.
+func imageDiff(got, want image.Image) float64 {
+	if got.Bounds() != want.Bounds() {
+		return 1
+	}
+
+	var (
+		bounds = got.Bounds()
+		limit  = 35215 * pixelThreshold * pixelThreshold
+		differ int
+	)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if colorDelta(got.At(x, y), want.At(x, y)) > limit {
+				differ++
+			}
+		}
+	}
+
+	return float64(differ) / float64(bounds.Dx()*bounds.Dy())
+}
+func colorDelta(a, b color.Color) float64 {
+	r1, g1, b1, _ := a.RGBA()
+	r2, g2, b2, _ := b.RGBA()
+	dr := (float64(r1) - float64(r2)) / 257
+	dg := (float64(g1) - float64(g2)) / 257
+	db := (float64(b1) - float64(b2)) / 257
+
+	y := 0.29889531*dr + 0.58662247*dg + 0.11448223*db
+	i := 0.59597799*dr - 0.27417610*dg - 0.32180189*db
+	q := 0.21147017*dr - 0.52261711*dg + 0.31114694*db
+
+	return 0.5053*y*y + 0.299*i*i + 0.1957*q*q
+}
+
+func svgDiff(got, want string) string {
+	gotTexts, wantTexts := svgText.FindAllString(got, -1), svgText.FindAllString(want, -1)
+	if len(gotTexts) != len(wantTexts) {
+		return fmt.Sprintf("%d text nodes, want %d", len(gotTexts), len(wantTexts))
+	}
+	for i := range gotTexts {
+		if gotTexts[i] != wantTexts[i] {
+			return fmt.Sprintf("text %q, want %q", gotTexts[i], wantTexts[i])
+		}
+	}
+
+	got, want = svgText.ReplaceAllString(got, "><"), svgText.ReplaceAllString(want, "><")
+	if svgNumber.ReplaceAllString(got, "0") != svgNumber.ReplaceAllString(want, "0") {
+		return "elements or attributes differ"
+	}
+	gotNumbers, wantNumbers := svgNumber.FindAllString(got, -1), svgNumber.FindAllString(want, -1)
+	for i := range gotNumbers {
+		a, _ := strconv.ParseFloat(gotNumbers[i], 64)
+		b, _ := strconv.ParseFloat(wantNumbers[i], 64)
+		if math.Abs(a-b) > svgTolerance {
+			return fmt.Sprintf("coordinate %s, want %s", gotNumbers[i], wantNumbers[i])
+		}
+	}
+
+	return ""
+}
+
+func TestImageDiff(t *testing.T) {
+	a := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	b := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	b.Set(1, 1, color.RGBA{255, 255, 255, 255})
+	b.Set(2, 2, color.RGBA{3, 3, 3, 255})
+
+	if share := imageDiff(a, b); share != 0.01 {
+		t.Errorf("imageDiff = %v, want one pixel of a hundred", share)
+	}
+	if share := imageDiff(a, image.NewRGBA(image.Rect(0, 0, 5, 5))); share != 1 {
+		t.Errorf("imageDiff of different sizes = %v", share)
+	}
+}
+
+func TestSvgDiff(t *testing.T) {
+	want := `<svg width="800"><path d="M 10.2 20 L 30 40"/><text x="5">22,50</text></svg>`
+	tests := []struct {
+		got  string
+		same bool
+	}{
+		{want, true},
+		{`<svg width="800"><path d="M 10.4 20.3 L 30 40"/><text x="5">22,50</text></svg>`, true},
+		{`<svg width="800"><path d="M 12 20 L 30 40"/><text x="5">22,50</text></svg>`, false},
+		{`<svg width="800"><path d="M 10.2 20 L 30 40"/><text x="5">22,60</text></svg>`, false},
+		{`<svg width="800"><path d="M 10.2 20 L 30 40 Z"/><text x="5">22,50</text></svg>`, false},
+	}
+
+	for _, test := range tests {
+		if diff := svgDiff(test.got, want); (diff == "") != test.same {
+			t.Errorf("svgDiff(%s) = %q", test.got, diff)
+		}
+	}
+}
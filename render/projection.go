@@ -0,0 +1,122 @@
+package render
+
+import (
+	"fmt"
+	"github.com/DKazakov/gdr-go/locale"
+	"github.com/DKazakov/gdr-go/market"
+	"github.com/DKazakov/gdr-go/valuation"
+	"github.com/wcharczuk/go-chart"
+	drawing "github.com/wcharczuk/go-chart/drawing"
+	"strings"
+)
+
+// summary tells the median value by the last vesting date and the chances
+// to reach the goals.
+func summary(projection *valuation.Projection) string {
+	if len(projection.Outcomes) == 0 {
+		return ""
+	}
+
+	last := projection.Outcomes[len(projection.Outcomes)-1]
+	text := locale.Tr("projection.summary", last.Grant.Vest.Format(locale.Current().Date), locale.Number(last.Percentiles[2]/1000))
+	for i, goal := range projection.Goals {
+		text += fmt.Sprintf(", %sk: %.0f%%", locale.Number(goal/1000), last.Chances[i]*100)
+	}
+
+	return text
+}
+
+func projectionChart(projection *valuation.Projection, imageWidth, imageHeight int) chart.Chart {
+	var (
+		series  = []chart.Series{}
+		theme   = CurrentTheme()
+		names   = []string{"95%", "75%", "50%", "25%", "5%"}
+		fills   = []drawing.Color{theme.FanOuter, theme.FanInner, {}, theme.FanOuter, theme.Background}
+		maximum float64
+	)
+
+	for i := range names {
+		values := projection.Percentiles[len(projection.Percentiles)-1-i]
+		if _, max := market.MinMax(values); max > maximum {
+			maximum = max
+		}
+		series = append(series, chart.ContinuousSeries{
+			Name: locale.Tr("projection.percentile", names[i], locale.Number(values[len(values)-1]/1000)),
+			Style: chart.Style{
+				Show:        true,
+				StrokeColor: theme.Price,
+				StrokeWidth: 1.0,
+				FillColor:   fills[i],
+			},
+			XValues: market.Stamps(projection.Y),
+			YValues: values,
+		})
+	}
+	for i, goal := range projection.Goals {
+		chances := []string{}
+		for _, outcome := range projection.Outcomes {
+			chances = append(chances, fmt.Sprintf("%.0f%%", outcome.Chances[i]*100))
+		}
+		series = append(series, chart.ContinuousSeries{
+			Name: locale.Tr("projection.goal", locale.Number(goal/1000), strings.Join(chances, ", ")),
+			Style: chart.Style{
+				Show:        true,
+				StrokeColor: theme.Goal,
+				StrokeWidth: 1.0,
+			},
+			XValues: []float64{market.Stamp(projection.Y[0]), market.Stamp(projection.Y[len(projection.Y)-1])},
+			YValues: []float64{goal, goal},
+		})
+		if goal > maximum {
+			maximum = goal
+		}
+	}
+	for _, outcome := range projection.Outcomes {
+		vest := market.Stamp(outcome.Grant.Vest)
+		series = append(series, chart.ContinuousSeries{
+			Name: locale.Tr("projection.grant", outcome.Grant.Vest.Format(locale.Current().Date), locale.Number(outcome.Grant.Count), locale.Float(outcome.Grant.Strike, 2), locale.Number(outcome.Percentiles[2]/1000)),
+			Style: chart.Style{
+				Show:        true,
+				StrokeColor: theme.Gdr,
+				StrokeWidth: 1.0,
+			},
+			XValues: []float64{vest, vest},
+			YValues: []float64{0, maximum},
+		})
+	}
+
+	graph := chart.Chart{
+		Width:  imageWidth,
+		Height: imageHeight,
+		Background: chart.Style{
+			Padding: chart.Box{
+				Top: 20,
+			},
+		},
+		XAxis: chart.XAxis{
+			Style: chart.Style{
+				Show:     true,
+				FontSize: 7.0,
+			},
+			TickPosition:   chart.TickPositionBetweenTicks,
+			ValueFormatter: market.MonthsValueFormatter,
+		},
+		YAxis: chart.YAxis{
+			Style: chart.Style{
+				Show:     true,
+				FontSize: 7.0,
+			},
+			Range: &chart.ContinuousRange{
+				Min: 0,
+				Max: maximum * 1.05,
+			},
+			ValueFormatter: func(v interface{}) string {
+				return locale.Number(v.(float64)/1000) + "k"
+			},
+		},
+		Series: series,
+	}
+	theme.decorate(&graph)
+
+	return graph
+}
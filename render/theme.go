@@ -0,0 +1,153 @@
+package render
+
+import (
+	"github.com/wcharczuk/go-chart"
+	drawing "github.com/wcharczuk/go-chart/drawing"
+	"sync/atomic"
+)
+
+// Theme has chart colours and 256 colour indexes for the terminal.
+type Theme struct {
+	Background, Text, Axis               drawing.Color
+	Price, Waterline, Volume, Gdr        drawing.Color
+	Cursor, Goal, FanOuter, FanInner     drawing.Color
+	LadderOdd, LadderCurrent, LadderGoal int
+	LadderScenario                       int
+	StatusLoad, StatusDone, StatusError  int
+}
+
+var (
+	ThemeNames = []string{"light", "dark", "high-contrast", "deuteranopia"}
+	themes     = map[string]*Theme{
+		"light": {
+			Background:     drawing.Color{R: 255, G: 255, B: 255, A: 255},
+			Text:           drawing.Color{R: 51, G: 51, B: 51, A: 255},
+			Axis:           drawing.Color{R: 51, G: 51, B: 51, A: 255},
+			Price:          drawing.Color{R: 255, G: 0, B: 0, A: 255},
+			Waterline:      drawing.Color{R: 0, G: 0, B: 255, A: 255},
+			Volume:         drawing.Color{R: 0, G: 255, B: 0, A: 255},
+			Gdr:            drawing.Color{R: 0, G: 0, B: 0, A: 255},
+			Cursor:         drawing.Color{R: 128, G: 128, B: 128, A: 255},
+			Goal:           drawing.Color{R: 0, G: 0, B: 255, A: 255},
+			FanOuter:       drawing.Color{R: 255, G: 210, B: 210, A: 255},
+			FanInner:       drawing.Color{R: 255, G: 150, B: 150, A: 255},
+			LadderOdd:      242,
+			LadderCurrent:  34,
+			LadderGoal:     196,
+			LadderScenario: 33,
+			StatusLoad:     33,
+			StatusDone:     32,
+			StatusError:    31,
+		},
+		"dark": {
+			Background:     drawing.Color{R: 30, G: 30, B: 30, A: 255},
+			Text:           drawing.Color{R: 220, G: 220, B: 220, A: 255},
+			Axis:           drawing.Color{R: 160, G: 160, B: 160, A: 255},
+			Price:          drawing.Color{R: 255, G: 85, B: 85, A: 255},
+			Waterline:      drawing.Color{R: 100, G: 149, B: 237, A: 255},
+			Volume:         drawing.Color{R: 80, G: 220, B: 100, A: 255},
+			Gdr:            drawing.Color{R: 240, G: 240, B: 240, A: 255},
+			Cursor:         drawing.Color{R: 180, G: 180, B: 180, A: 255},
+			Goal:           drawing.Color{R: 100, G: 149, B: 237, A: 255},
+			FanOuter:       drawing.Color{R: 90, G: 40, B: 40, A: 255},
+			FanInner:       drawing.Color{R: 150, G: 60, B: 60, A: 255},
+			LadderOdd:      238,
+			LadderCurrent:  28,
+			LadderGoal:     124,
+			LadderScenario: 25,
+			StatusLoad:     33,
+			StatusDone:     32,
+			StatusError:    31,
+		},
+		"high-contrast": {
+			Background:     drawing.Color{R: 0, G: 0, B: 0, A: 255},
+			Text:           drawing.Color{R: 255, G: 255, B: 255, A: 255},
+			Axis:           drawing.Color{R: 255, G: 255, B: 255, A: 255},
+			Price:          drawing.Color{R: 255, G: 255, B: 0, A: 255},
+			Waterline:      drawing.Color{R: 0, G: 255, B: 255, A: 255},
+			Volume:         drawing.Color{R: 0, G: 255, B: 0, A: 255},
+			Gdr:            drawing.Color{R: 255, G: 255, B: 255, A: 255},
+			Cursor:         drawing.Color{R: 255, G: 0, B: 255, A: 255},
+			Goal:           drawing.Color{R: 0, G: 255, B: 255, A: 255},
+			FanOuter:       drawing.Color{R: 90, G: 90, B: 0, A: 255},
+			FanInner:       drawing.Color{R: 170, G: 170, B: 0, A: 255},
+			LadderOdd:      240,
+			LadderCurrent:  22,
+			LadderGoal:     88,
+			LadderScenario: 18,
+			StatusLoad:     93,
+			StatusDone:     92,
+			StatusError:    91,
+		},
+		"deuteranopia": {
+			Background:     drawing.Color{R: 255, G: 255, B: 255, A: 255},
+			Text:           drawing.Color{R: 51, G: 51, B: 51, A: 255},
+			Axis:           drawing.Color{R: 51, G: 51, B: 51, A: 255},
+			Price:          drawing.Color{R: 0, G: 114, B: 178, A: 255},
+			Waterline:      drawing.Color{R: 230, G: 159, B: 0, A: 255},
+			Volume:         drawing.Color{R: 86, G: 180, B: 233, A: 255},
+			Gdr:            drawing.Color{R: 0, G: 0, B: 0, A: 255},
+			Cursor:         drawing.Color{R: 128, G: 128, B: 128, A: 255},
+			Goal:           drawing.Color{R: 213, G: 94, B: 0, A: 255},
+			FanOuter:       drawing.Color{R: 200, G: 225, B: 240, A: 255},
+			FanInner:       drawing.Color{R: 130, G: 185, B: 220, A: 255},
+			LadderOdd:      242,
+			LadderCurrent:  32,
+			LadderGoal:     208,
+			LadderScenario: 141,
+			StatusLoad:     37,
+			StatusDone:     34,
+			StatusError:    33,
+		},
+	}
+	// theme is switched from the UI while http handlers render charts
+	theme     atomic.Value
+	themeName = "light"
+)
+
+func SetTheme(name string) bool {
+	item, ok := themes[name]
+	if ok {
+		theme.Store(item)
+		themeName = name
+	}
+
+	return ok
+}
+func CurrentTheme() *Theme {
+	if item, ok := theme.Load().(*Theme); ok {
+		return item
+	}
+
+	return themes["light"]
+}
+func NextTheme() {
+	for i, name := range ThemeNames {
+		if name == themeName {
+			SetTheme(ThemeNames[(i+1)%len(ThemeNames)])
+			return
+		}
+	}
+	SetTheme(ThemeNames[0])
+
+	return
+}
+
+// decorate paints background, axes and legend of graph in the theme colours.
+func (self *Theme) decorate(graph *chart.Chart) {
+	graph.Background.FillColor = self.Background
+	graph.Canvas.FillColor = self.Background
+	for _, axis := range []*chart.Style{&graph.XAxis.Style, &graph.YAxis.Style, &graph.YAxisSecondary.Style} {
+		axis.FontColor = self.Text
+		axis.StrokeColor = self.Axis
+	}
+	graph.Elements = []chart.Renderable{
+		chart.LegendThin(graph, chart.Style{
+			FillColor:   self.Background,
+			FontColor:   self.Text,
+			StrokeColor: self.Axis,
+		}),
+	}
+
+	return
+}
@@ -0,0 +1,36 @@
+package tui
+
+import (
+	"github.com/DKazakov/gdr-go/locale"
+	"github.com/DKazakov/gdr-go/valuation"
+)
+
+// ExerciseLines describes every exercise method in the interface language.
+func ExerciseLines(exercise *valuation.Exercise) []string {
+	lines := []string{locale.Tr("exercise.head", locale.Float(exercise.Price, 2), locale.Float(exercise.Dollar, 2))}
+	if len(exercise.Outcomes) == 0 {
+		return append(lines, " "+locale.Tr("exercise.none"))
+	}
+
+	for _, outcome := range exercise.Outcomes {
+		lines = append(lines,
+			" "+locale.Tr("exercise.shares", locale.Tr("exercise."+outcome.Method), locale.Float(outcome.Shares, 2), locale.Number(outcome.Paid), locale.Number(outcome.Cash)),
+			"   "+locale.Tr("exercise.fees", locale.Number(outcome.Fees), locale.Number(outcome.Tax), locale.Number(outcome.Net)),
+		)
+	}
+
+	return lines
+}
+func (self Textinfo) exerciseLines() []string {
+	lines := ExerciseLines(new(valuation.Exercise).Init(self.lastprice, self.dollar))
+	if self.scenario > 0 {
+		dollar := self.dollar
+		if self.scenarioDollar > 0 {
+			dollar = self.scenarioDollar
+		}
+		lines = append(lines, "")
+		lines = append(lines, ExerciseLines(new(valuation.Exercise).Init(self.scenario, dollar))...)
+	}
+
+	return lines
+}
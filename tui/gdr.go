@@ -0,0 +1,38 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/DKazakov/gdr-go/locale"
+	"github.com/DKazakov/gdr-go/market"
+	"github.com/DKazakov/gdr-go/valuation"
+)
+
+func breakdownLines(data *market.Data) []string {
+	lines := []string{
+		locale.Tr("breakdown.formula"),
+		fmt.Sprintf("N = %s, strike = %s", locale.Number(valuation.OptionsValue), locale.Float(valuation.OptionsVesting, 2)),
+		locale.Tr("breakdown.model", market.Model.Window, market.Model.Price),
+		"",
+	}
+	lines = append(lines, gdrLines(data.GdrToday, locale.Tr("breakdown.gdr"))...)
+	lines = append(lines, "")
+	lines = append(lines, gdrLines(data.GdrNext, locale.Tr("breakdown.forecast"))...)
+
+	return lines
+}
+func gdrLines(breakdown market.GdrBreakdown, title string) []string {
+	lines := []string{title}
+	for _, bar := range breakdown.Bars {
+		date := market.Display(bar.Time).Format(locale.Current().Date)
+		if bar.Forecast {
+			date = locale.Tr("breakdown.today")
+		}
+		lines = append(lines, fmt.Sprintf(" %-10s %7s x %skk", date, locale.Float(bar.Price, 2), locale.Float(bar.Volume/1000000, 3)))
+	}
+	if breakdown.Session != "" {
+		lines = append(lines, " "+locale.Tr("breakdown.volume", locale.Tr("breakdown."+breakdown.Session)))
+	}
+	lines = append(lines, fmt.Sprintf(" %s %s => GDR %s", market.Model.Price, locale.Float(breakdown.Price, 2), locale.Float(breakdown.Gdr, 2)))
+
+	return lines
+}
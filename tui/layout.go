@@ -0,0 +1,207 @@
+package tui
+
+import (
+	"github.com/mattn/go-runewidth"
+	"github.com/nsf/termbox-go"
+)
+
+const (
+	sideLeft = iota
+	sideBottom
+	sideFill
+)
+
+type Rect struct {
+	x, y, width, height int
+}
+
+func (self Rect) contains(x, y int) bool {
+	return x >= self.x && x < self.x+self.width && y >= self.y && y < self.y+self.height
+}
+
+// Panel is a part of the screen. Panels are drawn into termbox cells, except
+// overlay ones, which print escape sequences (the chart image) after flush.
+type Panel struct {
+	side    int
+	visible bool
+	overlay bool
+	size    func(free Rect) int
+	natural int
+	delta   int
+	draw    func(rect Rect)
+	rect    Rect
+}
+
+// Layout cuts the screen in the order panels were added: every panel takes
+// its size from the side of the space left by the previous ones.
+type Layout struct {
+	width, height int
+	order         []string
+	panels        map[string]*Panel
+}
+
+func (self *Layout) Init() *Layout {
+	self.panels = map[string]*Panel{}
+	self.width, self.height = termbox.Size()
+
+	return self
+}
+
+func (self *Layout) add(name string, panel *Panel) {
+	self.order = append(self.order, name)
+	self.panels[name] = panel
+
+	return
+}
+
+// arrange places visible panels and tells whether an overlay panel moved, in
+// which case the terminal has to be fully redrawn to wipe the old image.
+func (self *Layout) arrange() (moved bool) {
+	self.width, self.height = termbox.Size()
+	free := Rect{0, 0, self.width, self.height}
+
+	for _, name := range self.order {
+		var (
+			panel = self.panels[name]
+			rect  Rect
+		)
+
+		if panel.visible {
+			switch panel.side {
+			case sideLeft:
+				panel.natural = panel.size(free)
+				size := limit(panel.natural+panel.delta, free.width)
+				rect = Rect{free.x, free.y, size, free.height}
+				free.x = free.x + size
+				free.width = free.width - size
+			case sideBottom:
+				panel.natural = panel.size(free)
+				size := limit(panel.natural+panel.delta, free.height)
+				rect = Rect{free.x, free.y + free.height - size, free.width, size}
+				free.height = free.height - size
+			default:
+				rect = free
+			}
+		}
+
+		if panel.overlay && rect != panel.rect {
+			moved = true
+		}
+		panel.rect = rect
+	}
+
+	return moved
+}
+func limit(size, max int) int {
+	if size < 0 {
+		return 0
+	}
+	if size > max {
+		return max
+	}
+
+	return size
+}
+
+// draw redraws the whole screen. termbox only sends the cells that changed,
+// so there is no need to clear the terminal on every refresh.
+func (self *Layout) draw() {
+	moved := self.arrange()
+
+	termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
+	for _, name := range self.order {
+		if panel := self.panels[name]; !panel.overlay && panel.rect.width > 0 && panel.rect.height > 0 {
+			panel.draw(panel.rect)
+		}
+	}
+	if moved {
+		termbox.Sync()
+	} else {
+		termbox.Flush()
+	}
+
+	for _, name := range self.order {
+		if panel := self.panels[name]; panel.overlay && panel.rect.width > 0 && panel.rect.height > 0 {
+			panel.draw(panel.rect)
+		}
+	}
+
+	return
+}
+
+// drawPanel redraws one cell panel without touching the rest of the screen.
+func (self *Layout) drawPanel(name string) {
+	panel, ok := self.panels[name]
+	if !ok || panel.overlay || panel.rect.width == 0 || panel.rect.height == 0 {
+		return
+	}
+
+	fill(panel.rect, termbox.ColorDefault)
+	panel.draw(panel.rect)
+	termbox.Flush()
+
+	return
+}
+
+func (self *Layout) toggle(name string) {
+	if panel, ok := self.panels[name]; ok {
+		panel.visible = !panel.visible
+	}
+
+	return
+}
+func (self *Layout) resize(name string, delta int) {
+	if panel, ok := self.panels[name]; ok && panel.visible {
+		panel.delta = panel.delta + delta
+		if panel.natural+panel.delta < 0 {
+			panel.delta = -panel.natural
+		}
+	}
+
+	return
+}
+
+func (self *Layout) panelAt(x, y int) (string, Rect) {
+	for _, name := range self.order {
+		if panel := self.panels[name]; panel.rect.contains(x, y) {
+			return name, panel.rect
+		}
+	}
+
+	return "", Rect{}
+}
+
+// drawText writes text into one row starting at x and fills the rest of the
+// row up to right with the background. It returns the column after the text.
+func drawText(x, y, right int, text string, bg termbox.Attribute) int {
+	for _, r := range text {
+		width := runewidth.RuneWidth(r)
+		if x+width > right {
+			break
+		}
+		termbox.SetCell(x, y, r, termbox.ColorDefault, bg)
+		x = x + width
+	}
+	end := x
+	for ; x < right; x++ {
+		termbox.SetCell(x, y, ' ', termbox.ColorDefault, bg)
+	}
+
+	return end
+}
+func fill(rect Rect, bg termbox.Attribute) {
+	for y := rect.y; y < rect.y+rect.height; y++ {
+		drawText(rect.x, y, rect.x+rect.width, "", bg)
+	}
+
+	return
+}
+
+// palette converts a 256 colour index to a termbox attribute for Output256.
+func palette(color int) termbox.Attribute {
+	if color <= 0 {
+		return termbox.ColorDefault
+	}
+
+	return termbox.Attribute(color + 1)
+}
@@ -0,0 +1,38 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/DKazakov/gdr-go/locale"
+	"github.com/DKazakov/gdr-go/market"
+	"github.com/DKazakov/gdr-go/provider"
+	"sort"
+	"time"
+)
+
+func sourceLines(sources map[string]*provider.Source) (lines []string) {
+	names := []string{}
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		var (
+			item    = sources[name]
+			stats   = item.Snapshot()
+			status  = stats.Status()
+			updated = "-"
+		)
+
+		if !stats.Updated.IsZero() {
+			updated = market.Display(stats.Updated).Format("15:04:05")
+		}
+		line := locale.Tr("log.source", name, locale.Tr("log."+status), stats.Code, updated, int64(stats.Duration/time.Millisecond), locale.Number(float64(stats.Size)))
+		if stats.LastError != "" {
+			line = fmt.Sprintf("%s  %s", line, stats.LastError)
+		}
+		lines = append(lines, line+"  "+item.Url)
+	}
+
+	return lines
+}
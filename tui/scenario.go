@@ -0,0 +1,62 @@
+package tui
+
+import (
+	"github.com/DKazakov/gdr-go/locale"
+	"github.com/DKazakov/gdr-go/valuation"
+	"strconv"
+	"strings"
+)
+
+func (self Textinfo) scenarioLines() []string {
+	var (
+		dollar = self.dollar
+		now    = new(valuation.Scenario).Init(self.lastprice, self.dollar, self.gdr)
+	)
+	if self.scenarioDollar > 0 {
+		dollar = self.scenarioDollar
+	}
+	then := new(valuation.Scenario).Init(self.scenario, dollar, valuation.GdrAt(self.scenario))
+
+	return []string{
+		locale.Tr("scenario.head", locale.Float(then.Price, 2), locale.Float(now.Price, 2), locale.Float(then.Dollar, 2), locale.Float(now.Dollar, 2)),
+		locale.Tr(
+			"scenario.options",
+			locale.Number(then.Gross), locale.Number(then.GrossRub), locale.Number(then.Net), locale.Number(then.Tax),
+			locale.Number(now.Gross), locale.Number(now.GrossRub), locale.Number(now.Net),
+		),
+		locale.Tr(
+			"scenario.gdr",
+			locale.Float(then.Gdr, 2), locale.Number(then.Dprice), locale.Number(then.Rprice),
+			locale.Float(now.Gdr, 2), locale.Number(now.Dprice), locale.Number(now.Rprice),
+		),
+	}
+}
+
+func (self *Textinfo) setScenario(price, dollar string) bool {
+	var (
+		err    error
+		values = []float64{self.lastprice, 0}
+	)
+
+	for i, input := range []string{price, dollar} {
+		input = strings.Replace(strings.TrimSpace(input), ",", ".", 1)
+		if input == "" {
+			continue
+		}
+		values[i], err = strconv.ParseFloat(input, 64)
+		if err != nil || values[i] <= 0 {
+			return false
+		}
+	}
+
+	self.scenario = values[0]
+	self.scenarioDollar = values[1]
+
+	return true
+}
+func (self *Textinfo) clearScenario() {
+	self.scenario = 0
+	self.scenarioDollar = 0
+
+	return
+}
@@ -0,0 +1,213 @@
+// Package tui is the terminal interface: the ladder, chart, status line,
+// info bar and log laid out on termbox cells.
+package tui
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/DKazakov/gdr-go/logging"
+	"github.com/DKazakov/gdr-go/provider"
+	"github.com/DKazakov/gdr-go/render"
+	"github.com/mattn/go-runewidth"
+	"github.com/nsf/termbox-go"
+)
+
+const logPanelHeight = 12
+
+// Screen puts the ladder, chart, status line, info bar and log on a layout.
+type Screen struct {
+	layout  *Layout
+	graph   *render.Graph
+	text    *Textinfo
+	sources map[string]*provider.Source
+	events  <-chan termbox.Event
+	message string
+	scroll  int
+}
+
+func (self *Screen) Init(graph *render.Graph, text *Textinfo, sources map[string]*provider.Source, events <-chan termbox.Event) *Screen {
+	self.graph = graph
+	self.text = text
+	self.sources = sources
+	self.events = events
+	self.layout = new(Layout).Init()
+
+	self.layout.add("log", &Panel{
+		side: sideBottom,
+		size: func(free Rect) int { return logPanelHeight },
+		draw: self.drawLog,
+	})
+	self.layout.add("info", &Panel{
+		side:    sideBottom,
+		visible: true,
+		size:    func(free Rect) int { return len(self.text.InfoLines()) },
+		draw:    self.drawInfo,
+	})
+	self.layout.add("status", &Panel{
+		side:    sideBottom,
+		visible: true,
+		size:    func(free Rect) int { return 1 },
+		draw:    self.drawStatus,
+	})
+	self.layout.add("ladder", &Panel{
+		side:    sideLeft,
+		visible: true,
+		size:    self.ladderWidth,
+		draw:    self.drawLadder,
+	})
+	self.layout.add("chart", &Panel{
+		side:    sideFill,
+		visible: true,
+		overlay: true,
+		draw:    self.drawChart,
+	})
+
+	return self
+}
+
+func (self *Screen) draw() {
+	self.layout.draw()
+
+	return
+}
+
+func (self *Screen) ladderWidth(free Rect) (width int) {
+	for _, line := range self.text.lines(free.height) {
+		if length := runewidth.StringWidth(line.text); length > width {
+			width = length
+		}
+	}
+
+	return width
+}
+func (self *Screen) drawLadder(rect Rect) {
+	for i, line := range self.text.lines(rect.height) {
+		if i >= rect.height {
+			break
+		}
+		drawText(rect.x, rect.y+i, rect.x+rect.width, line.text, palette(line.bg))
+	}
+
+	return
+}
+func (self *Screen) drawInfo(rect Rect) {
+	for i, line := range self.text.InfoLines() {
+		if i >= rect.height {
+			break
+		}
+		drawText(rect.x, rect.y+i, rect.x+rect.width, line, termbox.ColorDefault)
+	}
+
+	return
+}
+
+// drawChart prints the chart as an inline image over the rect, keeping the
+// terminal cursor where termbox left it.
+func (self *Screen) drawChart(rect Rect) {
+	imageWidth := rect.width * 7
+	imageHeight := rect.height * 15
+
+	image := self.graph.Render(imageWidth*2, imageHeight*2)
+	str := base64.StdEncoding.EncodeToString(image.Bytes())
+
+	fmt.Printf("\x1b7\x1b[%d;%dH\x1b]1337;File=name=none;size=%d;inline=1;width=%d;height=%d;preserveAspectRatio=0:%s\a\x1b8", rect.y+1, rect.x+1, len(str), rect.width, rect.height, str)
+
+	return
+}
+
+// drawStatus shows a notice, the cursor tooltip under the chart or the pages.
+func (self *Screen) drawStatus(rect Rect) {
+	chart := self.layout.panels["chart"].rect
+
+	if self.message != "" {
+		drawText(rect.x, rect.y, rect.x+rect.width, self.message, termbox.ColorDefault)
+	} else if tooltip := self.graph.Tooltip(); tooltip != "" {
+		drawText(chart.x, rect.y, rect.x+rect.width, tooltip, termbox.ColorDefault)
+	} else {
+		drawText(rect.x+rect.width/2-2, rect.y, rect.x+rect.width, self.graph.Paginate(), termbox.ColorDefault)
+	}
+
+	return
+}
+
+// drawLog shows the state of every source and the tail of the log above it.
+func (self *Screen) drawLog(rect Rect) {
+	sources := sourceLines(self.sources)
+	lines := logging.Tail.Lines(rect.height-len(sources), self.scroll)
+
+	for i, line := range lines {
+		drawText(rect.x, rect.y+i, rect.x+rect.width, line, termbox.ColorDefault)
+	}
+	for i, line := range sources {
+		if len(lines)+i >= rect.height {
+			break
+		}
+		drawText(rect.x, rect.y+len(lines)+i, rect.x+rect.width, line, palette(render.CurrentTheme().LadderOdd))
+	}
+
+	return
+}
+
+// scrollLog moves the log tail by lines, positive values go back in time.
+func (self *Screen) scrollLog(lines int) bool {
+	panel := self.layout.panels["log"]
+	if !panel.visible {
+		return false
+	}
+
+	self.scroll = limit(self.scroll+lines, logging.Tail.Len()-1)
+	self.layout.drawPanel("log")
+
+	return true
+}
+
+// notice shows text in the status line until the next redraw.
+func (self *Screen) notice(text string) {
+	self.message = text
+	self.layout.drawPanel("status")
+	self.message = ""
+
+	return
+}
+
+// prompt reads a line in the status line, Esc cancels the input. Data
+// updates wait until the input is done.
+func (self *Screen) prompt(label string) (string, bool) {
+	var (
+		input = []rune{}
+		rect  = self.layout.panels["status"].rect
+	)
+	defer termbox.HideCursor()
+
+	for {
+		x := drawText(rect.x, rect.y, rect.x+rect.width, label+string(input), termbox.ColorDefault)
+		termbox.SetCursor(x, rect.y)
+		termbox.Flush()
+
+		ev, ok := <-self.events
+		if !ok {
+			return "", false
+		}
+		if ev.Type != termbox.EventKey {
+			continue
+		}
+		switch ev.Key {
+		case termbox.KeyEnter:
+			return string(input), true
+		case termbox.KeyEsc:
+			return "", false
+		case termbox.KeyBackspace, termbox.KeyBackspace2:
+			if len(input) > 0 {
+				input = input[:len(input)-1]
+			}
+		case termbox.KeySpace:
+			input = append(input, ' ')
+		case 0:
+			input = append(input, ev.Ch)
+		}
+	}
+}
+
+func (self *Screen) panelAt(x, y int) (string, Rect) {
+	return self.layout.panelAt(x, y)
+}
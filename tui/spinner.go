@@ -0,0 +1,118 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"github.com/DKazakov/gdr-go/market"
+	"github.com/DKazakov/gdr-go/provider"
+	"github.com/DKazakov/gdr-go/render"
+	"github.com/nsf/termbox-go"
+	"strings"
+	"sync"
+	"time"
+)
+
+const loadTick time.Duration = 300 * time.Millisecond
+
+var (
+	loadingMu     sync.Mutex
+	loadingBuffer []string
+	loadingIndex  = map[*provider.Source]int{}
+)
+
+// loadSpinner draws loading progress until the returned stop is called,
+// stop waits for the last frame so the screen is free after it.
+func loadSpinner(x, y int) (stop func()) {
+	var (
+		spin    int
+		strBeg  = "load "
+		xbeg    = x/2 - (len(strBeg)+5)/2
+		ybeg    = y / 2
+		ticker  = time.NewTicker(loadTick)
+		done    = make(chan bool)
+		stopped = make(chan bool)
+	)
+
+	go func() {
+		defer close(stopped)
+
+		for {
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+			spin++
+
+			loadingMu.Lock()
+			lines := append([]string{}, loadingBuffer...)
+			loadingMu.Unlock()
+
+			termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
+
+			fmt.Print("\x1b[2J\x1b[0;0H")
+			for _, e := range lines {
+				fmt.Println(e)
+			}
+
+			fmt.Printf("\x1b[%d;%dH%s", ybeg, xbeg, strBeg+strings.Repeat(".", spin))
+			termbox.Flush()
+			if spin >= 5 {
+				spin = 0
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+		<-stopped
+	}
+}
+
+// load fetches item showing its progress under the spinner.
+func load(ctx context.Context, item *provider.Source) (data []market.GraphData, err error) {
+	setStatus(item, "load")
+	data, err = item.Get(ctx)
+	if err != nil {
+		setStatus(item, "error")
+	} else {
+		setStatus(item, "done")
+	}
+
+	return data, err
+}
+
+func setStatus(item *provider.Source, name string, color ...int) {
+	var (
+		statusColor int
+		status      string
+		theme       = render.CurrentTheme()
+	)
+	if name == "error" {
+		status = fmt.Sprintf("\x1b[05;%dm%s\x1b[0m", theme.StatusError, name)
+	} else {
+		if len(color) > 0 {
+			statusColor = color[0]
+		} else if name == "load" {
+			statusColor = theme.StatusLoad
+		} else {
+			statusColor = theme.StatusDone
+		}
+
+		status = fmt.Sprintf("\x1b[05;%dm%s\x1b[0m", statusColor, name)
+	}
+	statusString := fmt.Sprintf("%s: %s", item.Url, status)
+
+	loadingMu.Lock()
+	index, ok := loadingIndex[item]
+	if !ok {
+		loadingBuffer = append(loadingBuffer, statusString)
+		index = len(loadingBuffer) - 1
+		loadingIndex[item] = index
+	}
+	loadingBuffer[index] = statusString
+	loadingMu.Unlock()
+
+	return
+}
@@ -0,0 +1,148 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/DKazakov/gdr-go/locale"
+	"github.com/DKazakov/gdr-go/market"
+	"github.com/DKazakov/gdr-go/render"
+	"github.com/DKazakov/gdr-go/valuation"
+	"time"
+)
+
+type Textinfo struct {
+	gdr, gdrForecast     float64
+	lastprice, lastclose float64
+	lastupdate           string
+	dollar               float64
+	up                   bool
+	scenario             float64
+	scenarioDollar       float64
+	breakdown            []string
+	view                 int
+}
+
+func (self *Textinfo) Init(data *market.Data) *Textinfo {
+	self.gdr = data.Gdr
+	self.gdrForecast = data.GdrForecast
+	self.lastprice = data.LastPrice
+	self.lastclose = data.LastClose
+	self.dollar = data.Dollar
+	if !data.LastUpdate.Equal(time.Unix(0, 0)) {
+		self.lastupdate = market.Display(data.LastUpdate).Format("15:04:05")
+	} else {
+		self.lastupdate = "--:--:--"
+	}
+	self.up = data.LastPrice >= data.LastClose
+	self.breakdown = breakdownLines(data)
+
+	return self
+}
+
+const (
+	viewLadder = iota
+	viewExplain
+	viewExercise
+)
+
+// ladder is the valuation ladder at the current price with the scenario row.
+func (self Textinfo) ladder(count int) []valuation.LadderRow {
+	return valuation.Ladder(self.lastprice, self.dollar, self.scenario, count)
+}
+func (self *Textinfo) toggleView(view int) {
+	if self.view == view {
+		self.view = viewLadder
+	} else {
+		self.view = view
+	}
+
+	return
+}
+
+type TextLine struct {
+	text string
+	bg   int
+}
+
+func (self Textinfo) lines(rows int) []TextLine {
+	switch self.view {
+	case viewExplain:
+		return plain(self.breakdown)
+	case viewExercise:
+		return plain(self.exerciseLines())
+	}
+
+	return self.forecast(rows)
+}
+func plain(texts []string) (lines []TextLine) {
+	for _, text := range texts {
+		lines = append(lines, TextLine{text, 0})
+	}
+
+	return lines
+}
+func (self Textinfo) forecast(rows int) (lines []TextLine) {
+	var (
+		color int
+		theme = render.CurrentTheme()
+	)
+
+	for _, row := range self.ladder(rows) {
+		switch row.Kind {
+		case "current":
+			color = theme.LadderCurrent
+		case "goal":
+			color = theme.LadderGoal
+		case "scenario":
+			color = theme.LadderScenario
+		case "odd":
+			color = theme.LadderOdd
+		default:
+			color = 0
+		}
+
+		lines = append(lines, TextLine{fmt.Sprintf("%s: % 6s  % 5s", locale.Float(row.Price, 2), locale.Number(row.Value), locale.Number(row.Rvalue)), color})
+	}
+
+	return lines
+}
+func (self Textinfo) InfoLines() []string {
+	const (
+		smilegood = string(rune(128512))
+		smilebad  = string(rune(128545))
+	)
+	var (
+		smile          string
+		dprice         = self.gdr * self.lastprice
+		rprice         = dprice * self.dollar
+		rpriceForecast = self.gdrForecast * self.lastprice * self.dollar
+	)
+	if self.up {
+		smile = fmt.Sprintf("%s  (%s%s)", smilegood, "+", locale.Float(self.lastprice-self.lastclose, 2))
+	} else {
+		smile = fmt.Sprintf("%s  (%s)", smilebad, locale.Float(self.lastprice-self.lastclose, 2))
+	}
+
+	if self.scenario > 0 {
+		return self.scenarioLines()
+	}
+
+	return []string{
+		locale.Tr("info.price", locale.Float(self.lastprice, 2), smile, self.lastupdate, market.Display(time.Now()).Format("15:04:05")),
+		locale.Tr("info.gdr", locale.Float(self.gdr, 2), locale.Float(self.gdrForecast, 2), locale.Number(rpriceForecast)),
+		locale.Tr("info.total", locale.Number(dprice), locale.Number(rprice), locale.Float(self.dollar, 2)),
+	}
+}
+func (self *Textinfo) selectRow(count, row int) bool {
+	rows := self.ladder(count)
+	if row < 0 || row >= len(rows) {
+		return false
+	}
+
+	if self.scenario == rows[row].Price {
+		self.clearScenario()
+	} else {
+		self.scenario = rows[row].Price
+	}
+
+	return true
+}
@@ -0,0 +1,345 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"github.com/DKazakov/gdr-go/locale"
+	"github.com/DKazakov/gdr-go/logging"
+	"github.com/DKazakov/gdr-go/market"
+	"github.com/DKazakov/gdr-go/provider"
+	"github.com/DKazakov/gdr-go/render"
+	"github.com/nsf/termbox-go"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Options are where the interface saves charts and series, and Publish
+// is called with every update.
+type Options struct {
+	Export       *render.ExportOptions
+	SeriesFormat string
+	Publish      func(*market.Data)
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+
+	return i
+}
+
+// parseRange reads dates in the display zone, the range ends after the
+// last day.
+func parseRange(input string) (from, to time.Time, err error) {
+	parts := strings.Split(input, "-")
+	if len(parts) != 2 {
+		return from, to, fmt.Errorf("wrong range %q", input)
+	}
+
+	from, err = time.ParseInLocation(locale.Current().Date, strings.TrimSpace(parts[0]), market.Zone())
+	if err != nil {
+		return from, to, err
+	}
+	to, err = time.ParseInLocation(locale.Current().Date, strings.TrimSpace(parts[1]), market.Zone())
+	if err != nil {
+		return from, to, err
+	}
+
+	return from, to.AddDate(0, 0, 1), nil
+}
+
+// restore gives the terminal back when a goroutine other than Run panics.
+func restore() {
+	if r := recover(); r != nil {
+		termbox.Close()
+		panic(r)
+	}
+}
+
+// Run shows the interface until q, Esc or ctx is cancelled, fetching
+// sources every provider.UpdateTick.
+func Run(ctx context.Context, sources map[string]*provider.Source, options Options) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// stderr is the terminal now, the log is only kept for the log panel
+	if !logging.ToFile() {
+		logging.Detach()
+	}
+	termbox.Init()
+	termbox.SetOutputMode(termbox.Output256)
+	termbox.SetInputMode(termbox.InputEsc | termbox.InputMouse)
+	sizeX, sizeY := termbox.Size()
+	defer termbox.Close()
+
+	// PollEvent does not know about ctx, so a signal interrupts it
+	go func() {
+		<-ctx.Done()
+		termbox.Interrupt()
+	}()
+
+	stopSpinner := loadSpinner(sizeX, sizeY)
+
+	data, _ := provider.Update(ctx, sources, load, options.Publish)
+	time.Sleep(loadTick)
+	stopSpinner()
+	if ctx.Err() != nil {
+		return
+	}
+
+	// From here only this goroutine touches graph, text and the screen: the
+	// updater hands over new data and termbox events come through a channel.
+	events := make(chan termbox.Event)
+	go func() {
+		defer close(events)
+
+		for {
+			ev := termbox.PollEvent()
+			select {
+			case events <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	graph := new(render.Graph).Init(data)
+	text := new(Textinfo).Init(data)
+	screen := new(Screen).Init(graph, text, sources, events)
+	screen.draw()
+
+	updateTicker := time.NewTicker(provider.UpdateTick)
+	snapshots := make(chan *market.Data)
+	dragFrom := -1
+
+	go func() {
+		defer close(snapshots)
+		defer restore()
+
+		for {
+			select {
+			case <-updateTicker.C:
+			case <-ctx.Done():
+				return
+			}
+
+			next, _ := provider.Update(ctx, sources, provider.Get, options.Publish)
+			select {
+			case snapshots <- next:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	defer func() {
+		cancel()
+		updateTicker.Stop()
+		for _ = range snapshots {
+		}
+	}()
+
+loop:
+	for {
+		var ev termbox.Event
+
+		select {
+		case next, ok := <-snapshots:
+			if !ok {
+				break loop
+			}
+			data = next
+			graph.Init(data)
+			text.Init(data)
+			screen.draw()
+			continue
+		case item, ok := <-events:
+			if !ok {
+				break loop
+			}
+			ev = item
+		case <-ctx.Done():
+			break loop
+		}
+
+		switch ev.Type {
+		case termbox.EventKey:
+			switch ev.Key {
+			case termbox.KeyArrowLeft:
+				page := graph.PrevPage()
+				graph.SetPage(page)
+				screen.draw()
+			case termbox.KeyArrowRight:
+				page := graph.NextPage()
+				graph.SetPage(page)
+				screen.draw()
+			case termbox.KeyPgup:
+				screen.scrollLog(logPanelHeight / 2)
+			case termbox.KeyPgdn:
+				screen.scrollLog(-logPanelHeight / 2)
+			case termbox.KeySpace:
+				//graphType = getNextGraphType(true)
+				//renderGraph()
+			case termbox.KeyEsc:
+				break loop
+			case 0:
+				switch ev.Ch {
+				case 49, 50, 51, 52, 53, 54:
+					page := int(ev.Ch) - 49
+					graph.SetPage(page)
+					screen.draw()
+				case 101:
+					path, err := graph.Export(options.Export)
+					if err != nil {
+						logging.Error("export error", "err", err)
+						screen.notice(locale.Tr("notice.save_error"))
+					} else {
+						screen.notice(locale.Tr("notice.saved", filepath.Base(path)))
+					}
+				case 69:
+					paths, err := graph.ExportAll(options.Export)
+					if err != nil {
+						logging.Error("export error", "err", err)
+						screen.notice(locale.Tr("notice.save_error"))
+					} else {
+						screen.notice(locale.Tr("notice.saved_charts", len(paths)))
+					}
+				case 115:
+					paths, err := data.ExportSeries(options.Export.Dir, options.SeriesFormat)
+					if err != nil {
+						logging.Error("series export error", "err", err)
+						screen.notice(locale.Tr("notice.save_error"))
+					} else {
+						screen.notice(locale.Tr("notice.saved_series", len(paths)))
+					}
+				case 43, 61:
+					if graph.Zoom(0.5) {
+						screen.draw()
+					}
+				case 45:
+					if graph.Zoom(2) {
+						screen.draw()
+					}
+				case 91:
+					if graph.Pan(-0.25) {
+						screen.draw()
+					}
+				case 93:
+					if graph.Pan(0.25) {
+						screen.draw()
+					}
+				case 114:
+					input, ok := screen.prompt(locale.Tr("prompt.range"))
+					if ok {
+						from, to, err := parseRange(input)
+						if err != nil || !graph.SetRange(from, to) {
+							screen.notice(locale.Tr("notice.wrong_range"))
+						} else {
+							screen.draw()
+						}
+					} else {
+						screen.draw()
+					}
+				case 44:
+					graph.MoveCursor(-1)
+					screen.draw()
+				case 46:
+					graph.MoveCursor(1)
+					screen.draw()
+				case 99:
+					graph.HideCursor()
+					screen.draw()
+				case 119:
+					price, ok := screen.prompt(locale.Tr("prompt.price"))
+					if ok {
+						dollar, _ := screen.prompt(locale.Tr("prompt.dollar"))
+						if !text.setScenario(price, dollar) {
+							screen.notice(locale.Tr("notice.wrong_values"))
+							break
+						}
+					} else {
+						text.clearScenario()
+					}
+					screen.draw()
+				case 103:
+					text.toggleView(viewExplain)
+					screen.draw()
+				case 120:
+					text.toggleView(viewExercise)
+					screen.draw()
+				case 116:
+					render.NextTheme()
+					screen.draw()
+				case 108:
+					screen.layout.toggle("log")
+					screen.draw()
+				case 76:
+					screen.layout.toggle("ladder")
+					screen.draw()
+				case 73:
+					screen.layout.toggle("info")
+					screen.draw()
+				case 123:
+					screen.layout.resize("ladder", -2)
+					screen.draw()
+				case 125:
+					screen.layout.resize("ladder", 2)
+					screen.draw()
+				case 113:
+					break loop
+				default:
+					logging.Debug("unhandled event", "event", fmt.Sprintf("%+v", ev))
+				}
+			default:
+				logging.Debug("unhandled event", "event", fmt.Sprintf("%+v", ev))
+			}
+		case termbox.EventInterrupt:
+			break loop
+		case termbox.EventResize:
+			screen.draw()
+		case termbox.EventMouse:
+			var (
+				name, rect = screen.panelAt(ev.MouseX, ev.MouseY)
+				chart      = screen.layout.panels["chart"].rect
+				onChart    = name == "chart"
+				share      = float64(ev.MouseX-chart.x) / float64(chart.width)
+			)
+
+			switch ev.Key {
+			case termbox.MouseLeft:
+				if onChart && dragFrom < 0 {
+					dragFrom = ev.MouseX
+				}
+			case termbox.MouseRelease:
+				if onChart && dragFrom >= 0 && abs(ev.MouseX-dragFrom) > 1 {
+					from := graph.TimeAt(float64(dragFrom-chart.x) / float64(chart.width))
+					to := graph.TimeAt(share)
+					if from.After(to) {
+						from, to = to, from
+					}
+					if graph.SetRange(from, to) {
+						screen.draw()
+					}
+				} else if onChart {
+					graph.CursorAt(share)
+					screen.draw()
+				} else if page, ok := graph.PageAt(rect.width, ev.MouseX-rect.x); ok && name == "status" {
+					graph.SetPage(page)
+					screen.draw()
+				} else if name == "ladder" && text.view == viewLadder && text.selectRow(rect.height, ev.MouseY-rect.y) {
+					screen.draw()
+				}
+				dragFrom = -1
+			case termbox.MouseWheelUp:
+				if onChart && graph.Zoom(0.5) {
+					screen.draw()
+				}
+			case termbox.MouseWheelDown:
+				if onChart && graph.Zoom(2) {
+					screen.draw()
+				}
+			}
+		}
+	}
+}
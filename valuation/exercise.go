@@ -0,0 +1,48 @@
+package valuation
+
+type ExerciseOutcome struct {
+	Method string  `json:"method"`
+	Shares float64 `json:"shares"`
+	Paid   float64 `json:"paid_usd"`
+	Cash   float64 `json:"cash_usd"`
+	Fees   float64 `json:"fees_usd"`
+	Tax    float64 `json:"tax_rub"`
+	Net    float64 `json:"net_rub"`
+}
+type Exercise struct {
+	Price    float64           `json:"price"`
+	Dollar   float64           `json:"dollar"`
+	Outcomes []ExerciseOutcome `json:"outcomes"`
+}
+
+// Init compares exercising all options at price: paying the strike and
+// keeping every share, selling just enough to cover the strike (the GDR
+// number), or selling everything the same day. Tax is due on the exercise
+// gain in every case.
+func (self *Exercise) Init(price, dollar float64) *Exercise {
+	var (
+		gain = OptionsValue * (price - OptionsVesting)
+		tax  = gain * dollar * TaxRate
+		sold = OptionsValue * OptionsVesting / price
+	)
+	self.Price = price
+	self.Dollar = dollar
+	if gain <= 0 {
+		return self
+	}
+
+	cash := ExerciseOutcome{Method: "cash", Shares: OptionsValue, Paid: OptionsValue * OptionsVesting, Tax: tax}
+	cash.Net = (cash.Shares*price-cash.Paid)*dollar - tax
+
+	cover := ExerciseOutcome{Method: "sell-to-cover", Shares: OptionsValue - sold, Fees: sold * price * Config.Fee, Tax: tax}
+	cover.Cash = -cover.Fees
+	cover.Net = (cover.Shares*price+cover.Cash)*dollar - tax
+
+	sale := ExerciseOutcome{Method: "same-day sale", Fees: OptionsValue * price * Config.Fee, Tax: tax}
+	sale.Cash = gain - sale.Fees
+	sale.Net = sale.Cash*dollar - tax
+
+	self.Outcomes = []ExerciseOutcome{cash, cover, sale}
+
+	return self
+}
@@ -0,0 +1,50 @@
+package valuation
+
+import (
+	"math"
+)
+
+// LadderRow is the option value at Price: Value in dollars and Rvalue in
+// thousands of rubles. Kind marks the current, goal and scenario prices,
+// the other rows are even or odd.
+type LadderRow struct {
+	Price  float64 `json:"price"`
+	Value  float64 `json:"value"`
+	Rvalue float64 `json:"rvalue"`
+	Kind   string  `json:"kind"`
+}
+
+// Ladder lists count option values from a bit below price or the goal
+// price, whichever is lower, in steps of half a dollar. scenario is the
+// what-if price to mark, 0 for none.
+func Ladder(price, dollar, scenario float64, count int) (rows []LadderRow) {
+	const (
+		step = 0.5
+		mul  = 0.993
+	)
+	var (
+		kind      string
+		even      = true
+		goodprice = GoalValue/(dollar*OptionsValue) + OptionsVesting
+		start     = math.Min(float64(int(price-2)), float64(int(goodprice-2)))
+	)
+	for row := start; row < start+float64(count)*step; row = row + step {
+		if scenario > 0 && row == scenario {
+			kind = "scenario"
+		} else if row >= price*mul && row < price*mul+step {
+			kind = "current"
+		} else if row >= goodprice && row < goodprice+step {
+			kind = "goal"
+		} else if even {
+			kind = "even"
+		} else {
+			kind = "odd"
+		}
+		even = !even
+
+		value := OptionsValue * (row - OptionsVesting)
+		rows = append(rows, LadderRow{row, value, value * dollar / 1000, kind})
+	}
+
+	return rows
+}
@@ -0,0 +1,31 @@
+package valuation
+
+import (
+	"testing"
+)
+
+func TestLadder(t *testing.T) {
+	rows := Ladder(30, 75, 31, 40)
+	if len(rows) != 40 {
+		t.Fatalf("got %d rows, want 40", len(rows))
+	}
+
+	kinds := map[string]float64{}
+	for _, row := range rows {
+		if row.Value != OptionsValue*(row.Price-OptionsVesting) || row.Rvalue != row.Value*75/1000 {
+			t.Errorf("wrong value at %.2f: %.2f, %.2f", row.Price, row.Value, row.Rvalue)
+		}
+		kinds[row.Kind] = row.Price
+	}
+
+	goal := GoalValue/(75*OptionsValue) + OptionsVesting
+	if price := kinds["goal"]; price < goal || price >= goal+0.5 {
+		t.Errorf("goal row at %.2f, goal price %.2f", price, goal)
+	}
+	if price := kinds["current"]; price < 30*0.993 || price >= 30*0.993+0.5 {
+		t.Errorf("current row at %.2f", price)
+	}
+	if kinds["scenario"] != 31 {
+		t.Errorf("scenario row at %.2f, want 31", kinds["scenario"])
+	}
+}
@@ -0,0 +1,212 @@
+package valuation
+
+import (
+	"github.com/DKazakov/gdr-go/logging"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	simulationPaths = 2000
+	simulationStep  = 7 * 24 * time.Hour
+	yearDuration    = 365.25 * 24 * float64(time.Hour)
+)
+
+// Options are the broker fee, grants as count:strike:YYYY-MM-DD and value
+// goals in rubles, both comma separated. Config is used by the package.
+type Options struct {
+	Fee    float64
+	Grants string
+	Goals  string
+}
+
+var (
+	Config = Options{
+		Fee:   0.003,
+		Goals: strconv.FormatFloat(GoalValue, 'f', -1, 64),
+	}
+	// Percentiles of the projection fan, from the lowest
+	Percentiles = []float64{0.05, 0.25, 0.5, 0.75, 0.95}
+)
+
+type Grant struct {
+	Count, Strike float64
+	Vest          time.Time
+}
+type GrantOutcome struct {
+	Grant       Grant
+	Percentiles []float64
+	Chances     []float64
+}
+type Projection struct {
+	Y                 []time.Time
+	Percentiles       [][]float64
+	Outcomes          []GrantOutcome
+	Goals             []float64
+	Drift, Volatility float64
+}
+
+// GetGrants parses Config.Grants, without it all options vest in a year.
+func GetGrants() (grants []Grant) {
+	if Config.Grants == "" {
+		return []Grant{{OptionsValue, OptionsVesting, time.Now().AddDate(1, 0, 0)}}
+	}
+
+	for _, item := range strings.Split(Config.Grants, ",") {
+		parts := strings.Split(strings.TrimSpace(item), ":")
+		if len(parts) != 3 {
+			logging.Warn("wrong grant", "grant", item)
+			continue
+		}
+		count, err1 := strconv.ParseFloat(parts[0], 64)
+		strike, err2 := strconv.ParseFloat(parts[1], 64)
+		vest, err3 := time.ParseInLocation("2006-01-02", parts[2], time.Local)
+		if err1 != nil || err2 != nil || err3 != nil {
+			logging.Warn("wrong grant", "grant", item)
+			continue
+		}
+		grants = append(grants, Grant{count, strike, vest})
+	}
+	sort.Slice(grants, func(i, j int) bool { return grants[i].Vest.Before(grants[j].Vest) })
+
+	return grants
+}
+func GetGoals() (goals []float64) {
+	for _, item := range strings.Split(Config.Goals, ",") {
+		goal, err := strconv.ParseFloat(strings.TrimSpace(item), 64)
+		if err == nil && goal > 0 {
+			goals = append(goals, goal)
+		}
+	}
+
+	return goals
+}
+
+// estimate returns yearly drift and volatility of log returns of prices
+// taken at times.
+func estimate(times []time.Time, prices []float64) (drift, volatility float64) {
+	var (
+		returns []float64
+		years   float64
+		mean    float64
+		squares float64
+	)
+
+	for i := 1; i < len(prices); i++ {
+		if prices[i-1] <= 0 || prices[i] <= 0 {
+			continue
+		}
+		returns = append(returns, math.Log(prices[i]/prices[i-1]))
+		years = years + float64(times[i].Sub(times[i-1]))/yearDuration
+	}
+	if len(returns) < 2 || years <= 0 {
+		return 0, 0
+	}
+
+	for _, r := range returns {
+		mean = mean + r
+	}
+	mean = mean / float64(len(returns))
+	for _, r := range returns {
+		squares = squares + (r-mean)*(r-mean)
+	}
+	step := years / float64(len(returns))
+
+	return mean / step, math.Sqrt(squares / float64(len(returns)-1) / step)
+}
+
+// Init simulates the price from now to the last vesting date on the drift
+// and volatility of the price history and collects the portfolio value.
+func (self *Projection) Init(times []time.Time, prices []float64, price, dollar float64, grants []Grant, goals []float64) *Projection {
+	var (
+		now    = time.Now()
+		random = rand.New(rand.NewSource(now.UnixNano()))
+		dt     = float64(simulationStep) / yearDuration
+	)
+
+	if len(grants) == 0 || price <= 0 || !grants[len(grants)-1].Vest.After(now) {
+		return nil
+	}
+	self.Drift, self.Volatility = estimate(times, prices)
+	self.Goals = goals
+
+	steps := int(grants[len(grants)-1].Vest.Sub(now)/simulationStep) + 1
+	values := make([][]float64, steps+1)
+	vested := make([][]float64, len(grants))
+	for i := range values {
+		self.Y = append(self.Y, now.Add(time.Duration(i)*simulationStep))
+	}
+
+	for path := 0; path < simulationPaths; path++ {
+		next := 0
+		current := price
+		for i := range values {
+			if i > 0 {
+				// the drift is of log returns, it already has the volatility drag
+				current = current * math.Exp(self.Drift*dt+self.Volatility*math.Sqrt(dt)*random.NormFloat64())
+			}
+			values[i] = append(values[i], portfolio(grants, current, dollar))
+
+			for ; next < len(grants) && !grants[next].Vest.After(self.Y[i]); next++ {
+				vested[next] = append(vested[next], portfolio(grants[:next+1], current, dollar))
+			}
+		}
+	}
+
+	self.Percentiles = make([][]float64, len(Percentiles))
+	for _, column := range values {
+		sort.Float64s(column)
+		for p, share := range Percentiles {
+			self.Percentiles[p] = append(self.Percentiles[p], quantile(column, share))
+		}
+	}
+
+	for i, grant := range grants {
+		outcome := GrantOutcome{Grant: grant}
+		sort.Float64s(vested[i])
+		for _, share := range Percentiles {
+			outcome.Percentiles = append(outcome.Percentiles, quantile(vested[i], share))
+		}
+		for _, goal := range goals {
+			outcome.Chances = append(outcome.Chances, chance(vested[i], goal))
+		}
+		self.Outcomes = append(self.Outcomes, outcome)
+	}
+
+	return self
+}
+
+func grantValue(grant Grant, price, dollar float64) float64 {
+	return grant.Count * math.Max(price-grant.Strike, 0) * dollar
+}
+func portfolio(grants []Grant, price, dollar float64) (value float64) {
+	for _, grant := range grants {
+		value = value + grantValue(grant, price, dollar)
+	}
+
+	return value
+}
+func quantile(sorted []float64, share float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	return sorted[int(share*float64(len(sorted)-1))]
+}
+func chance(values []float64, goal float64) float64 {
+	reached := 0
+	for _, value := range values {
+		if value >= goal {
+			reached++
+		}
+	}
+	if len(values) == 0 {
+		return 0
+	}
+
+	return float64(reached) / float64(len(values))
+}
@@ -0,0 +1,36 @@
+package valuation
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// TestProjectionMedian simulates a year on five years of weekly prices that
+// swing around 30 without a trend, the median has to stay near 30.
+func TestProjectionMedian(t *testing.T) {
+	var (
+		times  []time.Time
+		prices []float64
+		begin  = time.Now().AddDate(-5, 0, 0)
+	)
+	for i := 0; i <= 260; i++ {
+		times = append(times, begin.Add(time.Duration(i)*simulationStep))
+		prices = append(prices, 30*math.Exp(0.08*float64(i%2)))
+	}
+
+	grants := []Grant{{Count: 1, Strike: 0, Vest: time.Now().AddDate(1, 0, 0)}}
+	projection := new(Projection).Init(times, prices, 30, 1, grants, nil)
+	if projection == nil {
+		t.Fatal("no projection")
+	}
+	if math.Abs(projection.Drift) > 1e-9 || projection.Volatility < 0.5 {
+		t.Fatalf("drift %v and volatility %v, want no drift and about 0.58", projection.Drift, projection.Volatility)
+	}
+
+	// the median of 2000 paths is within 0.5 of 30 in one standard error
+	median := projection.Outcomes[0].Percentiles[2]
+	if math.Abs(median-30) > 2 {
+		t.Errorf("median in a year is %.2f, want about 30", median)
+	}
+}
@@ -0,0 +1,41 @@
+// Package valuation values the options: GDR count on cashless exercise,
+// what-if scenarios, exercise methods and Monte Carlo projections.
+package valuation
+
+const (
+	OptionsValue   float64 = 1775
+	OptionsVesting float64 = 19.6
+	GoalValue      float64 = 1.65 * 1000000
+	TaxRate                = 0.13
+)
+
+// GdrAt returns the net GDR count left after selling enough of them at
+// price to pay the strike.
+func GdrAt(price float64) float64 {
+	return OptionsValue - (OptionsValue * OptionsVesting / price)
+}
+
+type Scenario struct {
+	Price, Dollar   float64
+	Gross, GrossRub float64
+	Tax, Net        float64
+	Gdr             float64
+	Dprice, Rprice  float64
+}
+
+func (self *Scenario) Init(price, dollar, gdr float64) *Scenario {
+	self.Price = price
+	self.Dollar = dollar
+	self.Gross = OptionsValue * (price - OptionsVesting)
+	if self.Gross < 0 {
+		self.Gross = 0
+	}
+	self.GrossRub = self.Gross * dollar
+	self.Tax = self.GrossRub * TaxRate
+	self.Net = self.GrossRub - self.Tax
+	self.Gdr = gdr
+	self.Dprice = gdr * price
+	self.Rprice = self.Dprice * dollar
+
+	return self
+}